@@ -1,20 +1,27 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/isutare412/crawlert/internal/bolt"
 	"github.com/isutare412/crawlert/internal/config"
 	"github.com/isutare412/crawlert/internal/core/port"
 	"github.com/isutare412/crawlert/internal/discord"
+	"github.com/isutare412/crawlert/internal/email"
 	"github.com/isutare412/crawlert/internal/http"
 	"github.com/isutare412/crawlert/internal/log"
+	"github.com/isutare412/crawlert/internal/memory"
 	"github.com/isutare412/crawlert/internal/pipeline"
+	"github.com/isutare412/crawlert/internal/slack"
 	"github.com/isutare412/crawlert/internal/telegram"
+	"github.com/isutare412/crawlert/internal/webhook"
 )
 
 var configPath = flag.String("configs", ".", "path to config directory")
@@ -33,16 +40,24 @@ func main() {
 
 	httpCrawler := http.NewCrawler()
 
-	messageSenders, err := buildMessageSenders(cfg)
+	receivers, err := buildReceivers(cfg)
 	if err != nil {
-		slog.Error("failed to build message senders", "error", err)
+		slog.Error("failed to build receivers", "error", err)
+		os.Exit(1)
+	}
+
+	store, err := buildStore(cfg)
+	if err != nil {
+		slog.Error("failed to build store", "error", err)
 		os.Exit(1)
 	}
 
 	pipelineProcessor, err := pipeline.NewProcessor(
 		cfg.ToPipelineProcessorConfig(),
 		httpCrawler,
-		messageSenders)
+		receivers,
+		store,
+		store)
 	if err != nil {
 		slog.Error("failed to create pipeline processor", "error", err)
 		os.Exit(1)
@@ -51,6 +66,18 @@ func main() {
 	pipelineProcessor.Run()
 	waitUntilSignal()
 	pipelineProcessor.Shutdown()
+
+	pending, err := pipelineProcessor.CountPendingMessages(context.Background())
+	switch {
+	case err != nil:
+		slog.Error("failed to count pending messages", "error", err)
+	case pending > 0:
+		slog.Warn("undelivered messages are kept in outbox", "pending", pending)
+	}
+
+	if err := store.Close(); err != nil {
+		slog.Error("failed to close store", "error", err)
+	}
 }
 
 func waitUntilSignal() {
@@ -74,8 +101,20 @@ func loadConfig() (*config.Config, error) {
 	return cfg, nil
 }
 
-func buildMessageSenders(cfg *config.Config) ([]port.MessageSender, error) {
-	switch cfg.Alerts.Type {
+func buildReceivers(cfg *config.Config) (map[string][]port.MessageSender, error) {
+	receivers := make(map[string][]port.MessageSender)
+	for _, rc := range cfg.Alerts.ReceiverConfigs() {
+		senders, err := buildReceiverMessageSenders(rc)
+		if err != nil {
+			return nil, fmt.Errorf("building message senders of receiver %s: %w", rc.Name, err)
+		}
+		receivers[rc.Name] = senders
+	}
+	return receivers, nil
+}
+
+func buildReceiverMessageSenders(cfg config.ReceiverConfig) ([]port.MessageSender, error) {
+	switch cfg.Type {
 	case "telegram":
 		var senders []port.MessageSender
 		for _, c := range cfg.ToTelegramMessageSenderConfigs() {
@@ -88,7 +127,40 @@ func buildMessageSenders(cfg *config.Config) ([]port.MessageSender, error) {
 			senders = append(senders, discord.NewMessageSender(c))
 		}
 		return senders, nil
+	case "slack":
+		var senders []port.MessageSender
+		for _, c := range cfg.ToSlackMessageSenderConfigs() {
+			senders = append(senders, slack.NewMessageSender(c))
+		}
+		return senders, nil
+	case "email":
+		sender, err := email.NewMessageSender(cfg.ToEmailMessageSenderConfig())
+		if err != nil {
+			return nil, fmt.Errorf("creating email message sender: %w", err)
+		}
+		return []port.MessageSender{sender}, nil
+	case "webhook":
+		return []port.MessageSender{webhook.NewMessageSender(cfg.ToWebhookMessageSenderConfig())}, nil
 	default:
-		return nil, fmt.Errorf("unknown alerts type: %s", cfg.Alerts.Type)
+		return nil, fmt.Errorf("unknown receiver type: %s", cfg.Type)
+	}
+}
+
+type store interface {
+	port.StateStore
+	port.Outbox
+	io.Closer
+}
+
+func buildStore(cfg *config.Config) (store, error) {
+	if cfg.State.Path == "" {
+		slog.Warn("state path is empty; crawl states are kept in memory and lost on restart")
+		return memory.NewStore(), nil
+	}
+
+	s, err := bolt.NewStore(cfg.ToBoltStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("creating bolt store: %w", err)
 	}
+	return s, nil
 }
@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/core/port"
+)
+
+// tokenRequestTimeout is the timeout of OAuth2 token requests.
+const tokenRequestTimeout = 30 * time.Second
+
+// Provider creates authenticators. OAuth2 tokens are cached and refreshed
+// before expiry, and shared by authenticators with the same credentials.
+type Provider struct {
+	client *http.Client
+
+	mu           sync.Mutex
+	tokenSources map[string]oauth2.TokenSource
+}
+
+func NewProvider() *Provider {
+	return &Provider{
+		client:       &http.Client{Timeout: tokenRequestTimeout},
+		tokenSources: make(map[string]oauth2.TokenSource),
+	}
+}
+
+// NewAuthenticator returns the authenticator of cfg, or nil if cfg has no
+// authentication type.
+func (p *Provider) NewAuthenticator(cfg AuthenticatorConfig) (port.Authenticator, error) {
+	switch cfg.Type {
+	case "":
+		return nil, nil
+	case TypeBasic:
+		return &basicAuthenticator{username: cfg.Basic.Username, password: cfg.Basic.Password}, nil
+	case TypeBearer:
+		return newBearerAuthenticator(cfg.Bearer)
+	case TypeOAuth2:
+		return &oauth2Authenticator{tokenSource: p.tokenSource(cfg.OAuth2)}, nil
+	default:
+		return nil, fmt.Errorf("unknown auth type '%s'", cfg.Type)
+	}
+}
+
+func (p *Provider) tokenSource(cfg OAuth2Config) oauth2.TokenSource {
+	key := tokenSourceKey(cfg)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ts, ok := p.tokenSources[key]; ok {
+		return ts
+	}
+
+	params := make(map[string][]string, len(cfg.EndpointParams))
+	for k, v := range cfg.EndpointParams {
+		params[k] = []string{v}
+	}
+
+	ccCfg := clientcredentials.Config{
+		ClientID:       cfg.ClientID,
+		ClientSecret:   cfg.ClientSecret,
+		TokenURL:       cfg.TokenURL,
+		Scopes:         cfg.Scopes,
+		EndpointParams: params,
+
+		// Credentials are sent in the header or in the body, whichever the
+		// token endpoint accepts.
+		AuthStyle: oauth2.AuthStyleAutoDetect,
+	}
+
+	// Tokens are fetched with the context of the token source rather than of
+	// each request, so that a cancelled crawl does not fail the shared token.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, p.client)
+	ts := ccCfg.TokenSource(ctx)
+	p.tokenSources[key] = ts
+	return ts
+}
+
+// tokenSourceKey returns the identity of OAuth2 credentials.
+func tokenSourceKey(cfg OAuth2Config) string {
+	params := make([]string, 0, len(cfg.EndpointParams))
+	for k, v := range cfg.EndpointParams {
+		params = append(params, k+"="+v)
+	}
+	slices.Sort(params)
+
+	scopes := slices.Clone(cfg.Scopes)
+	slices.Sort(scopes)
+
+	return strings.Join([]string{
+		cfg.TokenURL,
+		cfg.ClientID,
+		cfg.ClientSecret,
+		strings.Join(scopes, " "),
+		strings.Join(params, "&"),
+	}, "\x00")
+}
+
+type basicAuthenticator struct {
+	username string
+	password string
+}
+
+func (a *basicAuthenticator) Authenticate(_ context.Context, req *domain.CrawlRequest) error {
+	credentials := base64.StdEncoding.EncodeToString([]byte(a.username + ":" + a.password))
+	setAuthorization(req, "Basic "+credentials)
+	return nil
+}
+
+type bearerAuthenticator struct {
+	token     string
+	tokenFile string
+}
+
+func newBearerAuthenticator(cfg BearerConfig) (*bearerAuthenticator, error) {
+	switch {
+	case cfg.TokenFile != "":
+		return &bearerAuthenticator{tokenFile: cfg.TokenFile}, nil
+	case cfg.TokenEnv != "":
+		token := os.Getenv(cfg.TokenEnv)
+		if token == "" {
+			return nil, fmt.Errorf("environment variable %s of bearer token is empty", cfg.TokenEnv)
+		}
+		return &bearerAuthenticator{token: token}, nil
+	default:
+		return &bearerAuthenticator{token: cfg.Token}, nil
+	}
+}
+
+func (a *bearerAuthenticator) Authenticate(_ context.Context, req *domain.CrawlRequest) error {
+	token := a.token
+	if a.tokenFile != "" {
+		content, err := os.ReadFile(a.tokenFile)
+		if err != nil {
+			return fmt.Errorf("reading bearer token file: %w", err)
+		}
+		token = strings.TrimSpace(string(content))
+	}
+
+	setAuthorization(req, "Bearer "+token)
+	return nil
+}
+
+type oauth2Authenticator struct {
+	tokenSource oauth2.TokenSource
+}
+
+func (a *oauth2Authenticator) Authenticate(_ context.Context, req *domain.CrawlRequest) error {
+	token, err := a.tokenSource.Token()
+	if err != nil {
+		return fmt.Errorf("getting oauth2 token: %w", err)
+	}
+
+	setAuthorization(req, token.Type()+" "+token.AccessToken)
+	return nil
+}
+
+// setAuthorization sets Authorization header of req without modifying the
+// header shared with other requests.
+func setAuthorization(req *domain.CrawlRequest, value string) {
+	header := req.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Authorization", value)
+	req.Header = header
+}
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/core/port"
+)
+
+func TestProvider_NewAuthenticator(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("from-file\n"), 0o600))
+	t.Setenv("CRAWLERT_TEST_TOKEN", "from-env")
+
+	tests := []struct {
+		name string
+		cfg  AuthenticatorConfig
+		want string
+	}{
+		{
+			name: "basic",
+			cfg:  AuthenticatorConfig{Type: TypeBasic, Basic: BasicConfig{Username: "user", Password: "pass"}},
+			want: "Basic dXNlcjpwYXNz",
+		},
+		{
+			name: "bearer_token",
+			cfg:  AuthenticatorConfig{Type: TypeBearer, Bearer: BearerConfig{Token: "static"}},
+			want: "Bearer static",
+		},
+		{
+			name: "bearer_token_file",
+			cfg:  AuthenticatorConfig{Type: TypeBearer, Bearer: BearerConfig{TokenFile: tokenFile}},
+			want: "Bearer from-file",
+		},
+		{
+			name: "bearer_token_env",
+			cfg:  AuthenticatorConfig{Type: TypeBearer, Bearer: BearerConfig{TokenEnv: "CRAWLERT_TEST_TOKEN"}},
+			want: "Bearer from-env",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator, err := NewProvider().NewAuthenticator(tt.cfg)
+			require.NoError(t, err)
+
+			sharedHeader := http.Header{"Accept": {"application/json"}}
+			req := domain.CrawlRequest{Header: sharedHeader}
+			require.NoError(t, authenticator.Authenticate(context.Background(), &req))
+
+			assert.Equal(t, tt.want, req.Header.Get("Authorization"))
+			assert.Equal(t, "application/json", req.Header.Get("Accept"))
+			assert.Empty(t, sharedHeader.Get("Authorization"))
+		})
+	}
+}
+
+func TestProvider_NewAuthenticator_emptyTokenEnv(t *testing.T) {
+	_, err := NewProvider().NewAuthenticator(AuthenticatorConfig{
+		Type:   TypeBearer,
+		Bearer: BearerConfig{TokenEnv: "CRAWLERT_TEST_MISSING_TOKEN"},
+	})
+	assert.Error(t, err)
+}
+
+func TestProvider_NewAuthenticator_oauth2(t *testing.T) {
+	var tokenRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests.Add(1)
+
+		clientID, clientSecret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "client", clientID)
+		assert.Equal(t, "secret", clientSecret)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "read", r.PostForm.Get("scope"))
+		assert.Equal(t, "https://api.foo.com", r.PostForm.Get("audience"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "t0k",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	defer server.Close()
+
+	cfg := AuthenticatorConfig{
+		Type: TypeOAuth2,
+		OAuth2: OAuth2Config{
+			TokenURL:       server.URL,
+			ClientID:       "client",
+			ClientSecret:   "secret",
+			Scopes:         []string{"read"},
+			EndpointParams: map[string]string{"audience": "https://api.foo.com"},
+		},
+	}
+
+	provider := NewProvider()
+	first, err := provider.NewAuthenticator(cfg)
+	require.NoError(t, err)
+	second, err := provider.NewAuthenticator(cfg)
+	require.NoError(t, err)
+
+	for _, authenticator := range []port.Authenticator{first, second, first} {
+		req := domain.CrawlRequest{}
+		require.NoError(t, authenticator.Authenticate(context.Background(), &req))
+		assert.Equal(t, "Bearer t0k", req.Header.Get("Authorization"))
+	}
+
+	// The token is cached and shared by authenticators with the same
+	// credentials.
+	assert.Equal(t, int32(1), tokenRequests.Load())
+}
+
+func TestProvider_NewAuthenticator_oauth2CredentialsInBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		if _, _, ok := r.BasicAuth(); ok || r.PostForm.Get("client_secret") != "secret" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "t0k", "token_type": "Bearer"})
+	}))
+	defer server.Close()
+
+	authenticator, err := NewProvider().NewAuthenticator(AuthenticatorConfig{
+		Type: TypeOAuth2,
+		OAuth2: OAuth2Config{
+			TokenURL:     server.URL,
+			ClientID:     "client",
+			ClientSecret: "secret",
+		},
+	})
+	require.NoError(t, err)
+
+	req := domain.CrawlRequest{}
+	require.NoError(t, authenticator.Authenticate(context.Background(), &req))
+	assert.Equal(t, "Bearer t0k", req.Header.Get("Authorization"))
+}
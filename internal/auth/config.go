@@ -0,0 +1,61 @@
+package auth
+
+import "fmt"
+
+type AuthenticatorConfig struct {
+	Type   Type
+	Basic  BasicConfig
+	Bearer BearerConfig
+	OAuth2 OAuth2Config
+}
+
+// Type is the authentication scheme of crawl requests. Empty type means no
+// authentication.
+type Type string
+
+const (
+	TypeBasic  Type = "basic"
+	TypeBearer Type = "bearer"
+	TypeOAuth2 Type = "oauth2"
+)
+
+func (t Type) Validate() error {
+	switch t {
+	case "", TypeBasic, TypeBearer, TypeOAuth2:
+		return nil
+	default:
+		return fmt.Errorf("unknown auth type '%s'", t)
+	}
+}
+
+type BasicConfig struct {
+	Username string
+	Password string
+}
+
+// BearerConfig is the source of a bearer token. Only one of the sources is
+// set.
+type BearerConfig struct {
+	Token string
+
+	// TokenFile is the path of a file holding the token. The file is read on
+	// every request, so that rotated tokens are picked up.
+	TokenFile string
+
+	// TokenEnv is the name of an environment variable holding the token.
+	TokenEnv string
+}
+
+// OAuth2Config is an OAuth 2.0 client credentials grant. Client credentials
+// are sent with HTTP basic authentication first, and in the request body if
+// the token endpoint rejects them.
+type OAuth2Config struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	// EndpointParams are additional parameters of token requests, such as
+	// audience.
+	EndpointParams map[string]string
+}
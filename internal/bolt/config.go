@@ -0,0 +1,5 @@
+package bolt
+
+type StoreConfig struct {
+	Path string
+}
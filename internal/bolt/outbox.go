@@ -0,0 +1,106 @@
+package bolt
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+// bucketOutbox has a nested bucket per sender, whose keys are big-endian
+// message ids so that messages are iterated in the order of push.
+var bucketOutbox = []byte("outbox")
+
+func (s *Store) PushOutboxMessage(ctx context.Context, senderKey string, message domain.OutboxMessage) error {
+	value, err := json.Marshal(&message)
+	if err != nil {
+		return fmt.Errorf("marshaling outbox message: %w", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.Bucket(bucketOutbox).CreateBucketIfNotExists([]byte(senderKey))
+		if err != nil {
+			return fmt.Errorf("creating bucket of sender %s: %w", senderKey, err)
+		}
+
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return fmt.Errorf("generating message id: %w", err)
+		}
+
+		if err := bucket.Put(encodeID(id), value); err != nil {
+			return fmt.Errorf("putting outbox message: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *Store) PeekOutboxMessage(
+	ctx context.Context,
+	senderKey string,
+) (message domain.OutboxMessage, ok bool, err error) {
+	err = s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketOutbox).Bucket([]byte(senderKey))
+		if bucket == nil {
+			return nil
+		}
+
+		key, value := bucket.Cursor().First()
+		if key == nil {
+			return nil
+		}
+
+		if err := json.Unmarshal(value, &message); err != nil {
+			return fmt.Errorf("unmarshaling outbox message: %w", err)
+		}
+		message.ID = binary.BigEndian.Uint64(key)
+		ok = true
+		return nil
+	})
+	if err != nil {
+		return domain.OutboxMessage{}, false, err
+	}
+
+	return message, ok, nil
+}
+
+func (s *Store) DeleteOutboxMessage(ctx context.Context, senderKey string, id uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketOutbox).Bucket([]byte(senderKey))
+		if bucket == nil {
+			return nil
+		}
+
+		if err := bucket.Delete(encodeID(id)); err != nil {
+			return fmt.Errorf("deleting outbox message: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *Store) CountOutboxMessages(ctx context.Context) (map[string]int, error) {
+	counts := make(map[string]int)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketOutbox).ForEachBucket(func(senderKey []byte) error {
+			if n := tx.Bucket(bucketOutbox).Bucket(senderKey).Stats().KeyN; n > 0 {
+				counts[string(senderKey)] = n
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func encodeID(id uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, id)
+	return key
+}
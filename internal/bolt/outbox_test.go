@@ -0,0 +1,70 @@
+package bolt
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+func TestStore_Outbox(t *testing.T) {
+	ctx := context.Background()
+	cfg := StoreConfig{Path: filepath.Join(t.TempDir(), "crawlert.db")}
+
+	store, err := NewStore(cfg)
+	require.NoError(t, err)
+
+	_, ok, err := store.PeekOutboxMessage(ctx, "default/0")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	first := domain.OutboxMessage{
+		Message: domain.Message{
+			CrawlName: "foo",
+			Text:      "first",
+			Variables: map[string]string{"ONE": "1"},
+		},
+		CreatedAt: time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC),
+	}
+	second := domain.OutboxMessage{
+		Message:   domain.Message{CrawlName: "foo", Text: "second"},
+		CreatedAt: time.Date(2024, 10, 1, 9, 1, 0, 0, time.UTC),
+	}
+	require.NoError(t, store.PushOutboxMessage(ctx, "default/0", first))
+	require.NoError(t, store.PushOutboxMessage(ctx, "default/0", second))
+	require.NoError(t, store.PushOutboxMessage(ctx, "ops/1", second))
+	require.NoError(t, store.Close())
+
+	// Messages should survive reopening.
+	store, err = NewStore(cfg)
+	require.NoError(t, err)
+	defer store.Close()
+
+	counts, err := store.CountOutboxMessages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"default/0": 2, "ops/1": 1}, counts)
+
+	got, ok, err := store.PeekOutboxMessage(ctx, "default/0")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, first.Message, got.Message)
+	assert.Equal(t, first.CreatedAt, got.CreatedAt)
+
+	require.NoError(t, store.DeleteOutboxMessage(ctx, "default/0", got.ID))
+
+	got, ok, err = store.PeekOutboxMessage(ctx, "default/0")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, second.Message, got.Message)
+
+	require.NoError(t, store.DeleteOutboxMessage(ctx, "default/0", got.ID))
+
+	counts, err = store.CountOutboxMessages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"ops/1": 1}, counts)
+}
@@ -0,0 +1,87 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+var bucketCrawlStates = []byte("crawl_states")
+
+// Store persists data of crawlert into a single bbolt database file.
+type Store struct {
+	db *bolt.DB
+}
+
+func NewStore(cfg StoreConfig) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating directory of database: %w", err)
+	}
+
+	db, err := bolt.Open(cfg.Path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening database %s: %w", cfg.Path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketCrawlStates, bucketOutbox} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return fmt.Errorf("creating bucket %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{
+		db: db,
+	}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) GetCrawlState(ctx context.Context, crawlName string) (domain.CrawlState, error) {
+	var state domain.CrawlState
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(bucketCrawlStates).Get([]byte(crawlName))
+		if value == nil {
+			return nil
+		}
+
+		if err := json.Unmarshal(value, &state); err != nil {
+			return fmt.Errorf("unmarshaling crawl state: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.CrawlState{}, err
+	}
+
+	return state, nil
+}
+
+func (s *Store) PutCrawlState(ctx context.Context, crawlName string, state domain.CrawlState) error {
+	value, err := json.Marshal(&state)
+	if err != nil {
+		return fmt.Errorf("marshaling crawl state: %w", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(bucketCrawlStates).Put([]byte(crawlName), value); err != nil {
+			return fmt.Errorf("putting crawl state: %w", err)
+		}
+		return nil
+	})
+}
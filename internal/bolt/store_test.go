@@ -0,0 +1,47 @@
+package bolt
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+func TestStore_CrawlState(t *testing.T) {
+	ctx := context.Background()
+	cfg := StoreConfig{Path: filepath.Join(t.TempDir(), "data", "crawlert.db")}
+
+	store, err := NewStore(cfg)
+	require.NoError(t, err)
+
+	got, err := store.GetCrawlState(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Equal(t, domain.CrawlState{}, got)
+
+	want := domain.CrawlState{
+		LastResponseHash: "hash",
+		LastQueryResult: &domain.QueryResult{
+			Matched:   true,
+			Variables: map[string]string{"ONE": `["a"]`},
+		},
+		LastMatchedAt: time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC),
+		LastMessage:   "hello",
+		LastSentAt:    time.Date(2024, 10, 1, 9, 0, 1, 0, time.UTC),
+	}
+	require.NoError(t, store.PutCrawlState(ctx, "foo", want))
+	require.NoError(t, store.Close())
+
+	// States should survive reopening.
+	store, err = NewStore(cfg)
+	require.NoError(t, err)
+	defer store.Close()
+
+	got, err = store.GetCrawlState(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
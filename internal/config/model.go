@@ -3,19 +3,32 @@ package config
 import (
 	"fmt"
 	"net/http"
+	"net/mail"
 	"net/url"
+	"regexp"
 	"time"
 
+	"github.com/isutare412/crawlert/internal/auth"
+	"github.com/isutare412/crawlert/internal/bolt"
+	"github.com/isutare412/crawlert/internal/cron"
 	"github.com/isutare412/crawlert/internal/discord"
+	"github.com/isutare412/crawlert/internal/email"
+	"github.com/isutare412/crawlert/internal/format"
+	"github.com/isutare412/crawlert/internal/html"
 	"github.com/isutare412/crawlert/internal/log"
 	"github.com/isutare412/crawlert/internal/pipeline"
+	"github.com/isutare412/crawlert/internal/retry"
+	"github.com/isutare412/crawlert/internal/signing"
+	"github.com/isutare412/crawlert/internal/slack"
 	"github.com/isutare412/crawlert/internal/telegram"
+	"github.com/isutare412/crawlert/internal/webhook"
 )
 
 type Config struct {
 	Log    LogConfig     `koanf:"log"`
 	Crawls []CrawlConfig `koanf:"crawls"`
 	Alerts AlertsConfig  `koanf:"alerts"`
+	State  StateConfig   `koanf:"state"`
 }
 
 func (c Config) Validate() error {
@@ -26,10 +39,25 @@ func (c Config) Validate() error {
 		return fmt.Errorf("validating alerts config: %w", err)
 	}
 
+	receiverNames := make(map[string]struct{})
+	for _, r := range c.Alerts.ReceiverConfigs() {
+		receiverNames[r.Name] = struct{}{}
+	}
+	for _, name := range c.Alerts.DefaultReceivers {
+		if _, ok := receiverNames[name]; !ok {
+			return fmt.Errorf("unknown default receiver %q", name)
+		}
+	}
+
 	for _, cfg := range c.Crawls {
 		if err := cfg.Validate(); err != nil {
 			return fmt.Errorf("validating crawl config: %w", err)
 		}
+		for _, name := range cfg.Receivers {
+			if _, ok := receiverNames[name]; !ok {
+				return fmt.Errorf("unknown receiver %q of %s", name, cfg.Name)
+			}
+		}
 	}
 	return nil
 }
@@ -38,42 +66,37 @@ func (c Config) ToLogConfig() log.Config {
 	return log.Config(c.Log)
 }
 
-func (c Config) ToDiscordMessageSenderConfigs() []discord.MessageSenderConfig {
-	cfgs := make([]discord.MessageSenderConfig, 0, len(c.Alerts.Discord.WebhookURLs))
-	for _, url := range c.Alerts.Discord.WebhookURLs {
-		cfgs = append(cfgs, discord.MessageSenderConfig{
-			WebhookURL: url,
-		})
-	}
-	return cfgs
-}
-
-func (c Config) ToTelegramMessageSenderConfigs() []telegram.MessageSenderConfig {
-	cfgs := make([]telegram.MessageSenderConfig, 0, len(c.Alerts.Telegram.ChatIDs))
-	for _, id := range c.Alerts.Telegram.ChatIDs {
-		cfgs = append(cfgs, telegram.MessageSenderConfig{
-			BotToken: c.Alerts.Telegram.BotToken,
-			ChatID:   id,
-		})
-	}
-	return cfgs
+func (c Config) ToBoltStoreConfig() bolt.StoreConfig {
+	return bolt.StoreConfig(c.State)
 }
 
 func (c Config) ToPipelineProcessorConfig() pipeline.ProcessorConfig {
 	crawlCfgs := make([]pipeline.CrawlConfig, 0, len(c.Crawls))
 	for _, cfg := range c.Crawls {
+		receivers := c.Alerts.routeReceivers(cfg.Receivers)
+
 		crawlCfgs = append(crawlCfgs, pipeline.CrawlConfig{
-			Name:     cfg.Name,
-			Enabled:  cfg.Enabled,
-			Interval: cfg.Interval,
-			Target:   pipeline.CrawlTargetConfig{HTTP: pipeline.CrawlHTTPTargetConfig(cfg.Target.HTTP)},
-			Query:    pipeline.CrawlQueryConfig(cfg.Query),
-			Message:  cfg.Message,
+			Name:               cfg.Name,
+			Enabled:            cfg.Enabled,
+			Interval:           cfg.Interval,
+			Schedule:           cfg.Schedule,
+			Timezone:           cfg.Timezone,
+			Target:             cfg.Target.toPipelineConfig(),
+			Query:              pipeline.CrawlQueryConfig(cfg.Query),
+			Message:            cfg.Message,
+			ResolveMessage:     cfg.ResolveMessage,
+			NotifyOn:           cfg.NotifyOn,
+			Cooldown:           cfg.Cooldown,
+			QuietHours:         c.Alerts.QuietHours.overriddenBy(cfg.QuietHours).toPipelineConfig(),
+			Receivers:          receivers,
+			ReceiverQuietHours: c.Alerts.receiverQuietHours(receivers, cfg.QuietHours),
+			OnError:            pipeline.OnErrorConfig(cfg.OnError),
 		})
 	}
 
 	return pipeline.ProcessorConfig{
 		Crawls: crawlCfgs,
+		Outbox: c.Alerts.Outbox.toPipelineConfig(),
 	}
 }
 
@@ -94,41 +117,384 @@ func (c LogConfig) Validate() error {
 }
 
 type CrawlConfig struct {
-	Name     string            `koanf:"name"`
-	Enabled  bool              `koanf:"enabled"`
-	Interval time.Duration     `koanf:"interval"`
-	Target   CrawlTargetConfig `koanf:"target"`
-	Query    CrawlQueryConfig  `koanf:"query"`
-	Message  string            `koanf:"message"`
+	Name           string              `koanf:"name"`
+	Enabled        bool                `koanf:"enabled"`
+	Interval       time.Duration       `koanf:"interval"`
+	Schedule       string              `koanf:"schedule"`
+	Timezone       string              `koanf:"timezone"`
+	Target         CrawlTargetConfig   `koanf:"target"`
+	Query          CrawlQueryConfig    `koanf:"query"`
+	Message        string              `koanf:"message"`
+	ResolveMessage string              `koanf:"resolve-message"`
+	NotifyOn       pipeline.NotifyMode `koanf:"notify-on"`
+	Cooldown       time.Duration       `koanf:"cooldown"`
+	QuietHours     QuietHoursConfig    `koanf:"quiet-hours"`
+	Receivers      []string            `koanf:"receivers"`
+	OnError        OnErrorConfig       `koanf:"on-error"`
 }
 
 func (c CrawlConfig) Validate() error {
 	if c.Name == "" {
 		return fmt.Errorf("name should not be empty")
 	}
-	if c.Interval <= 0 {
-		return fmt.Errorf("interval %v of %s should not be empty or negative", c.Name, c.Interval)
+	switch {
+	case c.Schedule != "" && c.Interval != 0:
+		return fmt.Errorf("only one of interval and schedule of %s should be set", c.Name)
+	case c.Schedule != "":
+		if _, err := cron.Parse(c.Schedule, c.Timezone); err != nil {
+			return fmt.Errorf("validating schedule of %s: %w", c.Name, err)
+		}
+	case c.Timezone != "":
+		return fmt.Errorf("timezone of %s should be set only with schedule", c.Name)
+	case c.Interval <= 0:
+		return fmt.Errorf("interval %v of %s should not be empty or negative", c.Interval, c.Name)
 	}
 	if c.Message == "" {
 		return fmt.Errorf("message of %s should not be empty", c.Name)
 	}
+	if err := c.NotifyOn.Validate(); err != nil {
+		return fmt.Errorf("validating notify-on of %s: %w", c.Name, err)
+	}
+	if c.Cooldown < 0 {
+		return fmt.Errorf("cooldown %v of %s should not be negative", c.Cooldown, c.Name)
+	}
+	if err := c.QuietHours.Validate(); err != nil {
+		return fmt.Errorf("validating quiet hours of %s: %w", c.Name, err)
+	}
+	if err := c.OnError.Validate(); err != nil {
+		return fmt.Errorf("validating on-error of %s: %w", c.Name, err)
+	}
 
 	if err := c.Target.Validate(); err != nil {
 		return fmt.Errorf("validating target config of %s: %w", c.Name, err)
 	}
+	if c.Query.Mode == pipeline.QueryModeRegex && c.Target.decodesResponse() {
+		return fmt.Errorf("regex query of %s should be applied to a plain http target "+
+			"without format, html or pagination", c.Name)
+	}
+	query := c.Query
+	if c.Target.Feed.URL != "" && query.Items == "" {
+		query.Items, query.Key = pipeline.FeedQueries(query.Input)
+	}
+	if err := query.Validate(); err != nil {
+		return fmt.Errorf("validating query config of %s: %w", c.Name, err)
+	}
+	if c.ResolveMessage != "" && query.Items != "" {
+		return fmt.Errorf("resolve-message of %s is not supported with items, including feed targets", c.Name)
+	}
 
 	return nil
 }
 
 type CrawlTargetConfig struct {
-	HTTP CrawlHTTPTargetConfig `koanf:"http"`
+	HTTP    CrawlHTTPTargetConfig    `koanf:"http"`
+	Feed    CrawlFeedTargetConfig    `koanf:"feed"`
+	GraphQL CrawlGraphQLTargetConfig `koanf:"graphql"`
+	Steps   []CrawlStepConfig        `koanf:"steps"`
+	Auth    AuthConfig               `koanf:"auth"`
+	Signing SigningConfig            `koanf:"signing"`
 }
 
 func (c CrawlTargetConfig) Validate() error {
-	if err := c.HTTP.Validate(); err != nil {
-		return fmt.Errorf("validating http target: %w", err)
+	var targetCount int
+	targetsSet := []bool{c.HTTP.URL != "", c.Feed.URL != "", c.GraphQL.Endpoint != "", len(c.Steps) > 0}
+	for _, set := range targetsSet {
+		if set {
+			targetCount++
+		}
+	}
+	if targetCount > 1 {
+		return fmt.Errorf("only one of http, feed, graphql and steps target should be set")
+	}
+
+	switch {
+	case c.Feed.URL != "":
+		if err := c.Feed.Validate(); err != nil {
+			return fmt.Errorf("validating feed target: %w", err)
+		}
+	case c.GraphQL.Endpoint != "":
+		if err := c.GraphQL.Validate(); err != nil {
+			return fmt.Errorf("validating graphql target: %w", err)
+		}
+	case len(c.Steps) > 0:
+		if err := validateSteps(c.Steps); err != nil {
+			return fmt.Errorf("validating steps target: %w", err)
+		}
+	default:
+		if err := c.HTTP.Validate(); err != nil {
+			return fmt.Errorf("validating http target: %w", err)
+		}
+	}
+
+	if err := c.Auth.Validate(); err != nil {
+		return fmt.Errorf("validating auth: %w", err)
+	}
+	if err := c.Signing.Validate(); err != nil {
+		return fmt.Errorf("validating signing: %w", err)
+	}
+
+	// SigV4 signature is the Authorization header, which would overwrite the
+	// credentials of auth.
+	if c.Auth.Type != "" && c.Signing.Type == signing.TypeSigV4 {
+		return fmt.Errorf("auth should not be set with sigv4 signing")
+	}
+
+	return nil
+}
+
+// decodesResponse reports whether response bodies of the target are decoded
+// into JSON before queries are applied.
+func (c CrawlTargetConfig) decodesResponse() bool {
+	return c.Feed.URL != "" || c.GraphQL.Endpoint != "" || len(c.Steps) > 0 || len(c.HTTP.HTML.Fields) > 0 ||
+		(c.HTTP.Format != "" && c.HTTP.Format != format.FormatJSON) || c.HTTP.Pagination.Strategy != ""
+}
+
+func (c CrawlTargetConfig) toPipelineConfig() pipeline.CrawlTargetConfig {
+	return pipeline.CrawlTargetConfig{
+		HTTP: pipeline.CrawlHTTPTargetConfig{
+			Method: c.HTTP.Method,
+			URL:    c.HTTP.URL,
+			Header: c.HTTP.Header,
+			Body:   c.HTTP.Body,
+			Format: c.HTTP.Format,
+			HTML:   html.ExtractorConfig{Fields: toHTMLFieldConfigs(c.HTTP.HTML.Fields)},
+
+			Pagination: c.HTTP.Pagination.toPipelineConfig(),
+		},
+		Feed:    pipeline.CrawlFeedTargetConfig(c.Feed),
+		GraphQL: pipeline.CrawlGraphQLTargetConfig(c.GraphQL),
+		Steps:   toPipelineStepConfigs(c.Steps),
+		Auth:    c.Auth.toAuthenticatorConfig(),
+		Signing: c.Signing.toSignerConfig(),
+	}
+}
+
+type AuthConfig struct {
+	Type   auth.Type        `koanf:"type"`
+	Basic  BasicAuthConfig  `koanf:"basic"`
+	Bearer BearerAuthConfig `koanf:"bearer"`
+	OAuth2 OAuth2AuthConfig `koanf:"oauth2"`
+}
+
+func (c AuthConfig) Validate() error {
+	if err := c.Type.Validate(); err != nil {
+		return fmt.Errorf("validating type: %w", err)
+	}
+
+	switch c.Type {
+	case auth.TypeBasic:
+		if c.Basic.Username == "" {
+			return fmt.Errorf("username of basic auth should not be empty")
+		}
+	case auth.TypeBearer:
+		var sourceCount int
+		for _, set := range []bool{c.Bearer.Token != "", c.Bearer.TokenFile != "", c.Bearer.TokenEnv != ""} {
+			if set {
+				sourceCount++
+			}
+		}
+		if sourceCount != 1 {
+			return fmt.Errorf("exactly one of token, token-file and token-env of bearer auth should be set")
+		}
+	case auth.TypeOAuth2:
+		if _, err := url.Parse(c.OAuth2.TokenURL); err != nil || c.OAuth2.TokenURL == "" {
+			return fmt.Errorf("token url of oauth2 auth should be a valid url")
+		}
+		if c.OAuth2.ClientID == "" {
+			return fmt.Errorf("client id of oauth2 auth should not be empty")
+		}
+	}
+	return nil
+}
+
+func (c AuthConfig) toAuthenticatorConfig() auth.AuthenticatorConfig {
+	return auth.AuthenticatorConfig{
+		Type:   c.Type,
+		Basic:  auth.BasicConfig(c.Basic),
+		Bearer: auth.BearerConfig(c.Bearer),
+		OAuth2: auth.OAuth2Config(c.OAuth2),
+	}
+}
+
+type SigningConfig struct {
+	Type  signing.Type       `koanf:"type"`
+	HMAC  HMACSigningConfig  `koanf:"hmac"`
+	SigV4 SigV4SigningConfig `koanf:"sigv4"`
+}
+
+func (c SigningConfig) Validate() error {
+	if err := c.Type.Validate(); err != nil {
+		return fmt.Errorf("validating type: %w", err)
+	}
+
+	switch c.Type {
+	case signing.TypeHMAC:
+		if err := c.HMAC.Validate(); err != nil {
+			return fmt.Errorf("validating hmac: %w", err)
+		}
+	case signing.TypeSigV4:
+		if c.SigV4.Region == "" || c.SigV4.Service == "" {
+			return fmt.Errorf("region and service of sigv4 should not be empty")
+		}
+	}
+	return nil
+}
+
+func (c SigningConfig) toSignerConfig() signing.SignerConfig {
+	return signing.SignerConfig{
+		Type:  c.Type,
+		HMAC:  signing.HMACConfig(c.HMAC),
+		SigV4: signing.SigV4Config(c.SigV4),
+	}
+}
+
+type HMACSigningConfig struct {
+	Secret          string                  `koanf:"secret"`
+	StringToSign    string                  `koanf:"string-to-sign"`
+	Encoding        signing.Encoding        `koanf:"encoding"`
+	TimestampFormat signing.TimestampFormat `koanf:"timestamp-format"`
+	TimestampParam  string                  `koanf:"timestamp-param"`
+	SignatureHeader string                  `koanf:"signature-header"`
+	SignatureParam  string                  `koanf:"signature-param"`
+	Headers         map[string]string       `koanf:"headers"`
+}
+
+func (c HMACSigningConfig) Validate() error {
+	if c.Secret == "" {
+		return fmt.Errorf("secret should not be empty")
+	}
+	if c.StringToSign == "" {
+		return fmt.Errorf("string-to-sign should not be empty")
+	}
+	if err := c.Encoding.Validate(); err != nil {
+		return fmt.Errorf("validating encoding: %w", err)
+	}
+	if err := c.TimestampFormat.Validate(); err != nil {
+		return fmt.Errorf("validating timestamp format: %w", err)
+	}
+	if (c.SignatureHeader == "") == (c.SignatureParam == "") {
+		return fmt.Errorf("exactly one of signature-header and signature-param should be set")
+	}
+	return nil
+}
+
+type SigV4SigningConfig struct {
+	AccessKeyID     string `koanf:"access-key-id"`
+	SecretAccessKey string `koanf:"secret-access-key"`
+	SessionToken    string `koanf:"session-token"`
+	Region          string `koanf:"region"`
+	Service         string `koanf:"service"`
+}
+
+type BasicAuthConfig struct {
+	Username string `koanf:"username"`
+	Password string `koanf:"password"`
+}
+
+type BearerAuthConfig struct {
+	Token     string `koanf:"token"`
+	TokenFile string `koanf:"token-file"`
+	TokenEnv  string `koanf:"token-env"`
+}
+
+type OAuth2AuthConfig struct {
+	TokenURL       string            `koanf:"token-url"`
+	ClientID       string            `koanf:"client-id"`
+	ClientSecret   string            `koanf:"client-secret"`
+	Scopes         []string          `koanf:"scopes"`
+	EndpointParams map[string]string `koanf:"endpoint-params"`
+}
+
+type CrawlStepConfig struct {
+	Name    string            `koanf:"name"`
+	Method  string            `koanf:"method"`
+	URL     string            `koanf:"url"`
+	Header  map[string]string `koanf:"header"`
+	Body    string            `koanf:"body"`
+	Extract map[string]string `koanf:"extract"`
+	ForEach string            `koanf:"for-each"`
+}
+
+// validateSteps validates steps, and that for-each of each step refers to a
+// variable extracted by an earlier step.
+func validateSteps(steps []CrawlStepConfig) error {
+	var (
+		names     = make(map[string]struct{}, len(steps))
+		extracted = make(map[string]struct{})
+	)
+	for i, step := range steps {
+		if step.Name == "" {
+			return fmt.Errorf("name of step %d should not be empty", i)
+		}
+		if _, ok := names[step.Name]; ok {
+			return fmt.Errorf("duplicate step name %s", step.Name)
+		}
+		names[step.Name] = struct{}{}
+
+		switch step.Method {
+		case "", http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			return fmt.Errorf("unexpected method %s of step %s", step.Method, step.Name)
+		}
+		if step.URL == "" {
+			return fmt.Errorf("url of step %s should not be empty", step.Name)
+		}
+
+		if step.ForEach != "" {
+			if _, ok := extracted[step.ForEach]; !ok {
+				return fmt.Errorf("for-each of step %s should be a variable extracted by an earlier step", step.Name)
+			}
+		}
+		for key := range step.Extract {
+			extracted[key] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func toPipelineStepConfigs(steps []CrawlStepConfig) []pipeline.CrawlStepConfig {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	cfgs := make([]pipeline.CrawlStepConfig, 0, len(steps))
+	for _, step := range steps {
+		cfg := pipeline.CrawlStepConfig(step)
+		if cfg.Method == "" {
+			cfg.Method = http.MethodGet
+		}
+		cfgs = append(cfgs, cfg)
+	}
+	return cfgs
+}
+
+type CrawlGraphQLTargetConfig struct {
+	Endpoint      string            `koanf:"endpoint"`
+	Query         string            `koanf:"query"`
+	Variables     map[string]any    `koanf:"variables"`
+	OperationName string            `koanf:"operation-name"`
+	Header        map[string]string `koanf:"header"`
+}
+
+func (c CrawlGraphQLTargetConfig) Validate() error {
+	if _, err := url.Parse(c.Endpoint); err != nil {
+		return fmt.Errorf("parsing endpoint: %w", err)
+	}
+	if c.Query == "" {
+		return fmt.Errorf("query should not be empty")
 	}
+	return nil
+}
+
+type CrawlFeedTargetConfig struct {
+	URL    string            `koanf:"url"`
+	Header map[string]string `koanf:"header"`
+}
 
+func (c CrawlFeedTargetConfig) Validate() error {
+	if _, err := url.Parse(c.URL); err != nil {
+		return fmt.Errorf("parsing url: %w", err)
+	}
 	return nil
 }
 
@@ -137,6 +503,10 @@ type CrawlHTTPTargetConfig struct {
 	URL    string            `koanf:"url"`
 	Header map[string]string `koanf:"header"`
 	Body   string            `koanf:"body"`
+	Format format.Format     `koanf:"format"`
+	HTML   HTMLConfig        `koanf:"html"`
+
+	Pagination PaginationConfig `koanf:"pagination"`
 }
 
 func (c CrawlHTTPTargetConfig) Validate() error {
@@ -150,21 +520,359 @@ func (c CrawlHTTPTargetConfig) Validate() error {
 		return fmt.Errorf("parsing url: %w", err)
 	}
 
+	if err := c.Format.Validate(); err != nil {
+		return fmt.Errorf("validating format: %w", err)
+	}
+
+	if err := c.HTML.Validate(); err != nil {
+		return fmt.Errorf("validating html: %w", err)
+	}
+
+	if len(c.HTML.Fields) > 0 && c.Format != "" && c.Format != format.FormatJSON {
+		return fmt.Errorf("format %q should not be set with html", c.Format)
+	}
+
+	if err := c.Pagination.Validate(); err != nil {
+		return fmt.Errorf("validating pagination: %w", err)
+	}
+
+	return nil
+}
+
+const (
+	defaultPaginationLimitParam = "limit"
+	defaultPaginationItems      = "."
+	defaultPaginationMaxPages   = 10
+)
+
+// defaultPaginationParams are the default query parameters of each pagination
+// strategy.
+var defaultPaginationParams = map[pipeline.PaginationStrategy]string{
+	pipeline.PaginationPage:   "page",
+	pipeline.PaginationOffset: "offset",
+	pipeline.PaginationCursor: "cursor",
+}
+
+type PaginationConfig struct {
+	Strategy   pipeline.PaginationStrategy `koanf:"strategy"`
+	Param      string                      `koanf:"param"`
+	Start      *int                        `koanf:"start"`
+	LimitParam string                      `koanf:"limit-param"`
+	Limit      int                         `koanf:"limit"`
+	Cursor     string                      `koanf:"cursor"`
+	Items      string                      `koanf:"items"`
+	MaxPages   int                         `koanf:"max-pages"`
+}
+
+func (c PaginationConfig) Validate() error {
+	if err := c.Strategy.Validate(); err != nil {
+		return fmt.Errorf("validating strategy: %w", err)
+	}
+	if c.Strategy == pipeline.PaginationCursor && c.Cursor == "" {
+		return fmt.Errorf("cursor should not be empty for cursor strategy")
+	}
+	if c.Limit < 0 {
+		return fmt.Errorf("limit %d should not be negative", c.Limit)
+	}
+	if c.MaxPages < 0 {
+		return fmt.Errorf("max pages %d should not be negative", c.MaxPages)
+	}
+	return nil
+}
+
+func (c PaginationConfig) toPipelineConfig() pipeline.PaginationConfig {
+	if c.Strategy == "" {
+		return pipeline.PaginationConfig{}
+	}
+
+	cfg := pipeline.PaginationConfig{
+		Strategy:   c.Strategy,
+		Param:      c.Param,
+		LimitParam: c.LimitParam,
+		Limit:      c.Limit,
+		Cursor:     c.Cursor,
+		Items:      c.Items,
+		MaxPages:   c.MaxPages,
+	}
+	if cfg.Param == "" {
+		cfg.Param = defaultPaginationParams[c.Strategy]
+	}
+	switch {
+	case c.Start != nil:
+		cfg.Start = *c.Start
+	case c.Strategy == pipeline.PaginationPage:
+		cfg.Start = 1
+	}
+	if cfg.LimitParam == "" {
+		cfg.LimitParam = defaultPaginationLimitParam
+	}
+	if cfg.Items == "" {
+		cfg.Items = defaultPaginationItems
+	}
+	if cfg.MaxPages == 0 {
+		cfg.MaxPages = defaultPaginationMaxPages
+	}
+	return cfg
+}
+
+type HTMLConfig struct {
+	Fields map[string]HTMLFieldConfig `koanf:"fields"`
+}
+
+func (c HTMLConfig) Validate() error {
+	for name, f := range c.Fields {
+		if f.Selector == "" {
+			return fmt.Errorf("selector of field %s should not be empty", name)
+		}
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("validating field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+type HTMLFieldConfig struct {
+	Selector  string                     `koanf:"selector"`
+	Attribute string                     `koanf:"attribute"`
+	All       bool                       `koanf:"all"`
+	Fields    map[string]HTMLFieldConfig `koanf:"fields"`
+}
+
+func (c HTMLFieldConfig) Validate() error {
+	if c.Attribute != "" && len(c.Fields) > 0 {
+		return fmt.Errorf("only one of attribute and fields should be set")
+	}
+	for name, f := range c.Fields {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("validating field %s: %w", name, err)
+		}
+	}
 	return nil
 }
 
+func toHTMLFieldConfigs(cfgs map[string]HTMLFieldConfig) map[string]html.FieldConfig {
+	if len(cfgs) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]html.FieldConfig, len(cfgs))
+	for name, cfg := range cfgs {
+		fields[name] = html.FieldConfig{
+			Selector:  cfg.Selector,
+			Attribute: cfg.Attribute,
+			All:       cfg.All,
+			Fields:    toHTMLFieldConfigs(cfg.Fields),
+		}
+	}
+	return fields
+}
+
 type CrawlQueryConfig struct {
-	Check     string            `koanf:"check"`
-	Variables map[string]string `koanf:"variables"`
+	Mode      pipeline.QueryMode  `koanf:"mode"`
+	Input     pipeline.QueryInput `koanf:"input"`
+	Check     string              `koanf:"check"`
+	Variables map[string]string   `koanf:"variables"`
+	Items     string              `koanf:"items"`
+	Key       string              `koanf:"key"`
 }
 
+func (c CrawlQueryConfig) Validate() error {
+	if err := c.Mode.Validate(); err != nil {
+		return fmt.Errorf("validating mode: %w", err)
+	}
+	if err := c.Input.Validate(); err != nil {
+		return fmt.Errorf("validating input: %w", err)
+	}
+	if c.Mode == pipeline.QueryModeRegex {
+		return c.validateRegex()
+	}
+	if c.Check == "" && c.Items == "" {
+		return fmt.Errorf("check should not be empty unless items is set")
+	}
+	if c.Items != "" && c.Key == "" {
+		return fmt.Errorf("key should not be empty if items is set")
+	}
+	return nil
+}
+
+func (c CrawlQueryConfig) validateRegex() error {
+	switch {
+	case c.Input == pipeline.QueryInputEnvelope:
+		return fmt.Errorf("input should not be envelope in regex mode")
+	case c.Items != "" || c.Key != "":
+		return fmt.Errorf("items and key should not be set in regex mode")
+	case c.Check == "":
+		return fmt.Errorf("check should not be empty in regex mode")
+	}
+
+	if _, err := regexp.Compile(c.Check); err != nil {
+		return fmt.Errorf("compiling check regex: %w", err)
+	}
+	for key, expr := range c.Variables {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("compiling regex of variable %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+type OnErrorConfig struct {
+	Threshold       int    `koanf:"threshold"`
+	Message         string `koanf:"message"`
+	RecoveryMessage string `koanf:"recovery-message"`
+}
+
+func (c OnErrorConfig) Validate() error {
+	if c.Threshold < 0 {
+		return fmt.Errorf("threshold %d should not be negative", c.Threshold)
+	}
+	if c.Message == "" && c.RecoveryMessage != "" {
+		return fmt.Errorf("message should not be empty if recovery message is set")
+	}
+	return nil
+}
+
+// defaultReceiverName is the name of the receiver configured by type field of
+// alerts.
+const defaultReceiverName = "default"
+
 type AlertsConfig struct {
+	// Type and configs of each type define a single receiver named "default".
+	// Use Receivers to notify multiple receivers.
 	Type     string         `koanf:"type"`
 	Telegram TelegramConfig `koanf:"telegram"`
 	Discord  DiscordConfig  `koanf:"discord"`
+	Slack    SlackConfig    `koanf:"slack"`
+	Email    EmailConfig    `koanf:"email"`
+	Webhook  WebhookConfig  `koanf:"webhook"`
+
+	Receivers  []ReceiverConfig `koanf:"receivers"`
+	QuietHours QuietHoursConfig `koanf:"quiet-hours"`
+	Retry      RetryConfig      `koanf:"retry"`
+	Outbox     OutboxConfig     `koanf:"outbox"`
+
+	// DefaultReceivers are names of receivers of crawls without receivers.
+	// Every receiver is used if empty.
+	DefaultReceivers []string `koanf:"default-receivers"`
 }
 
 func (c AlertsConfig) Validate() error {
+	receivers := c.ReceiverConfigs()
+	if len(receivers) == 0 {
+		return fmt.Errorf("either type or receivers should be set")
+	}
+
+	names := make(map[string]struct{}, len(receivers))
+	for _, r := range receivers {
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("validating receiver %q: %w", r.Name, err)
+		}
+
+		if _, ok := names[r.Name]; ok {
+			return fmt.Errorf("duplicate receiver name %q", r.Name)
+		}
+		names[r.Name] = struct{}{}
+	}
+
+	if err := c.QuietHours.Validate(); err != nil {
+		return fmt.Errorf("validating quiet hours config: %w", err)
+	}
+	if err := c.Outbox.Validate(); err != nil {
+		return fmt.Errorf("validating outbox config: %w", err)
+	}
+	return nil
+}
+
+// ReceiverConfigs returns all receivers of alerts, including the receiver
+// defined by type field. Receivers without retry use retry of alerts.
+func (c AlertsConfig) ReceiverConfigs() []ReceiverConfig {
+	receivers := make([]ReceiverConfig, 0, len(c.Receivers)+1)
+	if c.Type != "" {
+		receivers = append(receivers, ReceiverConfig{
+			Name:     defaultReceiverName,
+			Type:     c.Type,
+			Telegram: c.Telegram,
+			Discord:  c.Discord,
+			Slack:    c.Slack,
+			Email:    c.Email,
+			Webhook:  c.Webhook,
+		})
+	}
+	receivers = append(receivers, c.Receivers...)
+
+	for i := range receivers {
+		if receivers[i].Retry == (RetryConfig{}) {
+			receivers[i].Retry = c.Retry
+		}
+	}
+	return receivers
+}
+
+// routeReceivers returns names of receivers of a crawl, falling back to default
+// receivers.
+func (c AlertsConfig) routeReceivers(names []string) []string {
+	if len(names) > 0 {
+		return names
+	}
+	if len(c.DefaultReceivers) > 0 {
+		return c.DefaultReceivers
+	}
+
+	receivers := c.ReceiverConfigs()
+	all := make([]string, 0, len(receivers))
+	for _, r := range receivers {
+		all = append(all, r.Name)
+	}
+	return all
+}
+
+// receiverQuietHours returns quiet hours of receivers overriding quiet hours of
+// alerts, keyed by receiver names. Quiet hours of a crawl override those of
+// receivers.
+func (c AlertsConfig) receiverQuietHours(names []string, crawl QuietHoursConfig) map[string]pipeline.QuietHoursConfig {
+	routed := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		routed[name] = struct{}{}
+	}
+
+	quietHours := make(map[string]pipeline.QuietHoursConfig)
+	for _, r := range c.ReceiverConfigs() {
+		if _, ok := routed[r.Name]; !ok || r.QuietHours.isZero() {
+			continue
+		}
+		quietHours[r.Name] = c.QuietHours.
+			overriddenBy(r.QuietHours).
+			overriddenBy(crawl).
+			toPipelineConfig()
+	}
+	return quietHours
+}
+
+type ReceiverConfig struct {
+	Name     string         `koanf:"name"`
+	Type     string         `koanf:"type"`
+	Telegram TelegramConfig `koanf:"telegram"`
+	Discord  DiscordConfig  `koanf:"discord"`
+	Slack    SlackConfig    `koanf:"slack"`
+	Email    EmailConfig    `koanf:"email"`
+	Webhook  WebhookConfig  `koanf:"webhook"`
+	Retry    RetryConfig    `koanf:"retry"`
+
+	// QuietHours overrides quiet hours of alerts for the receiver.
+	QuietHours QuietHoursConfig `koanf:"quiet-hours"`
+}
+
+func (c ReceiverConfig) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("name should not be empty")
+	}
+	if err := c.Retry.Validate(); err != nil {
+		return fmt.Errorf("validating retry config: %w", err)
+	}
+	if err := c.QuietHours.Validate(); err != nil {
+		return fmt.Errorf("validating quiet hours config: %w", err)
+	}
+
 	switch c.Type {
 	case "telegram":
 		if err := c.Telegram.Validate(); err != nil {
@@ -174,12 +882,227 @@ func (c AlertsConfig) Validate() error {
 		if err := c.Discord.Validate(); err != nil {
 			return fmt.Errorf("validating discord config: %w", err)
 		}
+	case "slack":
+		if err := c.Slack.Validate(); err != nil {
+			return fmt.Errorf("validating slack config: %w", err)
+		}
+	case "email":
+		if err := c.Email.Validate(); err != nil {
+			return fmt.Errorf("validating email config: %w", err)
+		}
+	case "webhook":
+		if err := c.Webhook.Validate(); err != nil {
+			return fmt.Errorf("validating webhook config: %w", err)
+		}
 	default:
-		return fmt.Errorf("unknown alerts type %q; expected \"telegram\" or \"discord\"", c.Type)
+		return fmt.Errorf("unknown receiver type %q; expected \"telegram\", \"discord\", \"slack\", \"email\" or \"webhook\"", c.Type)
 	}
 	return nil
 }
 
+func (c ReceiverConfig) ToDiscordMessageSenderConfigs() []discord.MessageSenderConfig {
+	cfgs := make([]discord.MessageSenderConfig, 0, len(c.Discord.WebhookURLs))
+	for _, url := range c.Discord.WebhookURLs {
+		cfgs = append(cfgs, discord.MessageSenderConfig{
+			WebhookURL: url,
+			Retry:      c.Retry.toPolicy(),
+		})
+	}
+	return cfgs
+}
+
+func (c ReceiverConfig) ToEmailMessageSenderConfig() email.MessageSenderConfig {
+	return email.MessageSenderConfig{
+		Host:     c.Email.Host,
+		Port:     c.Email.Port,
+		Security: c.Email.Security,
+		Auth:     c.Email.Auth,
+		Username: c.Email.Username,
+		Password: c.Email.Password,
+		From:     c.Email.From,
+		To:       c.Email.To,
+		Subject:  c.Email.Subject,
+		Retry:    c.Retry.toPolicy(),
+	}
+}
+
+func (c ReceiverConfig) ToSlackMessageSenderConfigs() []slack.MessageSenderConfig {
+	cfgs := make([]slack.MessageSenderConfig, 0, len(c.Slack.WebhookURLs))
+	for _, url := range c.Slack.WebhookURLs {
+		cfgs = append(cfgs, slack.MessageSenderConfig{
+			WebhookURL: url,
+			Retry:      c.Retry.toPolicy(),
+		})
+	}
+	return cfgs
+}
+
+func (c ReceiverConfig) ToTelegramMessageSenderConfigs() []telegram.MessageSenderConfig {
+	cfgs := make([]telegram.MessageSenderConfig, 0, len(c.Telegram.ChatIDs))
+	for _, id := range c.Telegram.ChatIDs {
+		cfgs = append(cfgs, telegram.MessageSenderConfig{
+			BotToken: c.Telegram.BotToken,
+			ChatID:   id,
+			Retry:    c.Retry.toPolicy(),
+		})
+	}
+	return cfgs
+}
+
+func (c ReceiverConfig) ToWebhookMessageSenderConfig() webhook.MessageSenderConfig {
+	return webhook.MessageSenderConfig{
+		URL:    c.Webhook.URL,
+		Method: c.Webhook.Method,
+		Header: c.Webhook.Header,
+		Format: c.Webhook.Format,
+		Retry:  c.Retry.toPolicy(),
+		Body:   c.Webhook.Body,
+	}
+}
+
+const (
+	defaultOutboxRelayInterval = 30 * time.Second
+	defaultOutboxMaxAge        = 24 * time.Hour
+)
+
+type OutboxConfig struct {
+	RelayInterval time.Duration `koanf:"relay-interval"`
+	MaxAge        time.Duration `koanf:"max-age"`
+}
+
+func (c OutboxConfig) Validate() error {
+	if c.RelayInterval < 0 {
+		return fmt.Errorf("relay interval %v should not be negative", c.RelayInterval)
+	}
+	if c.MaxAge < 0 {
+		return fmt.Errorf("max age %v should not be negative", c.MaxAge)
+	}
+	return nil
+}
+
+// toPipelineConfig converts c to pipeline config, filling empty fields with
+// defaults.
+func (c OutboxConfig) toPipelineConfig() pipeline.OutboxConfig {
+	cfg := pipeline.OutboxConfig(c)
+	if cfg.RelayInterval == 0 {
+		cfg.RelayInterval = defaultOutboxRelayInterval
+	}
+	if cfg.MaxAge == 0 {
+		cfg.MaxAge = defaultOutboxMaxAge
+	}
+	return cfg
+}
+
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = time.Second
+	defaultRetryMaxBackoff     = 30 * time.Second
+	defaultRetryMaxElapsed     = 2 * time.Minute
+)
+
+type RetryConfig struct {
+	MaxAttempts    int           `koanf:"max-attempts"`
+	InitialBackoff time.Duration `koanf:"initial-backoff"`
+	MaxBackoff     time.Duration `koanf:"max-backoff"`
+	MaxElapsed     time.Duration `koanf:"max-elapsed"`
+}
+
+func (c RetryConfig) Validate() error {
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("max attempts %d should not be negative", c.MaxAttempts)
+	}
+	if c.InitialBackoff < 0 {
+		return fmt.Errorf("initial backoff %v should not be negative", c.InitialBackoff)
+	}
+	if c.MaxBackoff < 0 {
+		return fmt.Errorf("max backoff %v should not be negative", c.MaxBackoff)
+	}
+	if c.MaxElapsed < 0 {
+		return fmt.Errorf("max elapsed %v should not be negative", c.MaxElapsed)
+	}
+	return nil
+}
+
+// toPolicy converts c to retry policy, filling empty fields with defaults.
+func (c RetryConfig) toPolicy() retry.Policy {
+	policy := retry.Policy(c)
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = defaultRetryMaxAttempts
+	}
+	if policy.InitialBackoff == 0 {
+		policy.InitialBackoff = defaultRetryInitialBackoff
+	}
+	if policy.MaxBackoff == 0 {
+		policy.MaxBackoff = defaultRetryMaxBackoff
+	}
+	if policy.MaxElapsed == 0 {
+		policy.MaxElapsed = defaultRetryMaxElapsed
+	}
+	return policy
+}
+
+// QuietHoursConfig is quiet hours of alerts, receivers or crawls. Each field set
+// overrides the same field of the lower level, in order of alerts, receivers and
+// crawls.
+type QuietHoursConfig struct {
+	// Enabled turns quiet hours of lower levels off if false.
+	Enabled  *bool               `koanf:"enabled"`
+	Mode     pipeline.QuietMode  `koanf:"mode"`
+	Timezone string              `koanf:"timezone"`
+	Windows  []QuietWindowConfig `koanf:"windows"`
+}
+
+func (c QuietHoursConfig) Validate() error {
+	if err := c.Mode.Validate(); err != nil {
+		return fmt.Errorf("validating mode: %w", err)
+	}
+	return c.toPipelineConfig().Validate()
+}
+
+func (c QuietHoursConfig) isZero() bool {
+	return c.Enabled == nil && c.Mode == "" && c.Timezone == "" && len(c.Windows) == 0
+}
+
+// overriddenBy returns c with fields set in o replaced.
+func (c QuietHoursConfig) overriddenBy(o QuietHoursConfig) QuietHoursConfig {
+	if o.Enabled != nil {
+		c.Enabled = o.Enabled
+	}
+	if o.Mode != "" {
+		c.Mode = o.Mode
+	}
+	if o.Timezone != "" {
+		c.Timezone = o.Timezone
+	}
+	if len(o.Windows) > 0 {
+		c.Windows = o.Windows
+	}
+	return c
+}
+
+func (c QuietHoursConfig) toPipelineConfig() pipeline.QuietHoursConfig {
+	if c.Enabled != nil && !*c.Enabled {
+		return pipeline.QuietHoursConfig{}
+	}
+
+	windows := make([]pipeline.QuietWindowConfig, 0, len(c.Windows))
+	for _, w := range c.Windows {
+		windows = append(windows, pipeline.QuietWindowConfig(w))
+	}
+
+	return pipeline.QuietHoursConfig{
+		Mode:     c.Mode,
+		Timezone: c.Timezone,
+		Windows:  windows,
+	}
+}
+
+type QuietWindowConfig struct {
+	Days  []string `koanf:"days"`
+	Start string   `koanf:"start"`
+	End   string   `koanf:"end"`
+}
+
 type DiscordConfig struct {
 	WebhookURLs []string `koanf:"webhook-urls"`
 }
@@ -191,6 +1114,85 @@ func (c DiscordConfig) Validate() error {
 	return nil
 }
 
+type SlackConfig struct {
+	WebhookURLs []string `koanf:"webhook-urls"`
+}
+
+func (c SlackConfig) Validate() error {
+	if len(c.WebhookURLs) == 0 {
+		return fmt.Errorf("webhook urls should not be empty")
+	}
+	for _, u := range c.WebhookURLs {
+		if _, err := url.Parse(u); err != nil {
+			return fmt.Errorf("parsing webhook url: %w", err)
+		}
+	}
+	return nil
+}
+
+type EmailConfig struct {
+	Host     string              `koanf:"host"`
+	Port     int                 `koanf:"port"`
+	Security email.Security      `koanf:"security"`
+	Auth     email.AuthMechanism `koanf:"auth"`
+	Username string              `koanf:"username"`
+	Password string              `koanf:"password"`
+	From     string              `koanf:"from"`
+	To       []string            `koanf:"to"`
+	Subject  string              `koanf:"subject"`
+}
+
+func (c EmailConfig) Validate() error {
+	if c.Host == "" {
+		return fmt.Errorf("host should not be empty")
+	}
+	if c.Port <= 0 || c.Port > 65535 {
+		return fmt.Errorf("port %d is out of range", c.Port)
+	}
+	if err := c.Security.Validate(); err != nil {
+		return fmt.Errorf("validating security: %w", err)
+	}
+	if err := c.Auth.Validate(); err != nil {
+		return fmt.Errorf("validating auth: %w", err)
+	}
+	if c.Auth != "" && c.Username == "" {
+		return fmt.Errorf("username should not be empty if auth is set")
+	}
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		return fmt.Errorf("parsing from address: %w", err)
+	}
+	if len(c.To) == 0 {
+		return fmt.Errorf("to addresses should not be empty")
+	}
+	for _, to := range c.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("parsing to address: %w", err)
+		}
+	}
+	return nil
+}
+
+type WebhookConfig struct {
+	URL    string            `koanf:"url"`
+	Method string            `koanf:"method"`
+	Header map[string]string `koanf:"header"`
+	Format webhook.Format    `koanf:"format"`
+	Body   string            `koanf:"body"`
+}
+
+func (c WebhookConfig) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("url should not be empty")
+	}
+	if _, err := url.Parse(c.URL); err != nil {
+		return fmt.Errorf("parsing url: %w", err)
+	}
+	if err := c.Format.Validate(); err != nil {
+		return fmt.Errorf("validating format: %w", err)
+	}
+	return nil
+}
+
 type TelegramConfig struct {
 	BotToken string   `koanf:"bot-token"`
 	ChatIDs  []string `koanf:"chat-ids"`
@@ -205,3 +1207,7 @@ func (c TelegramConfig) Validate() error {
 	}
 	return nil
 }
+
+type StateConfig struct {
+	Path string `koanf:"path"`
+}
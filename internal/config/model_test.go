@@ -0,0 +1,289 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/isutare412/crawlert/internal/auth"
+	"github.com/isutare412/crawlert/internal/format"
+	"github.com/isutare412/crawlert/internal/pipeline"
+	"github.com/isutare412/crawlert/internal/signing"
+)
+
+func TestAlertsConfig_Validate(t *testing.T) {
+	telegram := TelegramConfig{BotToken: "test-bot-token", ChatIDs: []string{"test-chat-id"}}
+	discord := DiscordConfig{WebhookURLs: []string{"https://discord.com/api/webhooks/foo"}}
+
+	tests := []struct {
+		name    string
+		cfg     AlertsConfig
+		wantErr bool
+	}{
+		{
+			name: "legacy_type",
+			cfg: AlertsConfig{
+				Type:     "telegram",
+				Telegram: telegram,
+			},
+		},
+		{
+			name: "mixed_receivers",
+			cfg: AlertsConfig{
+				Type:     "telegram",
+				Telegram: telegram,
+				Receivers: []ReceiverConfig{
+					{Name: "team-discord", Type: "discord", Discord: discord},
+					{Name: "team-telegram", Type: "telegram", Telegram: telegram},
+				},
+			},
+		},
+		{
+			name:    "no_receiver",
+			cfg:     AlertsConfig{},
+			wantErr: true,
+		},
+		{
+			name: "invalid_receiver",
+			cfg: AlertsConfig{
+				Receivers: []ReceiverConfig{
+					{Name: "team-discord", Type: "discord"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty_receiver_name",
+			cfg: AlertsConfig{
+				Receivers: []ReceiverConfig{
+					{Type: "discord", Discord: discord},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate_receiver_name",
+			cfg: AlertsConfig{
+				Type:     "telegram",
+				Telegram: telegram,
+				Receivers: []ReceiverConfig{
+					{Name: "default", Type: "discord", Discord: discord},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAlertsConfig_routeReceivers(t *testing.T) {
+	alerts := AlertsConfig{
+		Type: "telegram",
+		Receivers: []ReceiverConfig{
+			{Name: "pricing"},
+			{Name: "ops"},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		defaultReceivers []string
+		crawlReceivers   []string
+		want             []string
+	}{
+		{
+			name:             "crawl_receivers",
+			defaultReceivers: []string{"ops"},
+			crawlReceivers:   []string{"pricing"},
+			want:             []string{"pricing"},
+		},
+		{
+			name:             "default_receivers",
+			defaultReceivers: []string{"ops"},
+			want:             []string{"ops"},
+		},
+		{
+			name: "all_receivers",
+			want: []string{"default", "pricing", "ops"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts := alerts
+			alerts.DefaultReceivers = tt.defaultReceivers
+
+			got := alerts.routeReceivers(tt.crawlReceivers)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAlertsConfig_receiverQuietHours(t *testing.T) {
+	disabled := false
+	night := []QuietWindowConfig{{Start: "22:00", End: "08:00"}}
+	weekend := []QuietWindowConfig{{Days: []string{"sat", "sun"}, Start: "00:00", End: "00:00"}}
+
+	alerts := AlertsConfig{
+		Receivers: []ReceiverConfig{
+			{Name: "pricing"},
+			{Name: "ops", QuietHours: QuietHoursConfig{Mode: pipeline.QuietModeDigest, Windows: weekend}},
+			{Name: "oncall", QuietHours: QuietHoursConfig{Enabled: &disabled}},
+		},
+		QuietHours: QuietHoursConfig{Mode: pipeline.QuietModeDiscard, Timezone: "Asia/Seoul", Windows: night},
+	}
+	receivers := []string{"pricing", "ops", "oncall"}
+
+	tests := []struct {
+		name            string
+		crawlQuietHours QuietHoursConfig
+		wantDefault     pipeline.QuietHoursConfig
+		want            map[string]pipeline.QuietHoursConfig
+	}{
+		{
+			name: "receiver_overrides_alerts",
+			wantDefault: pipeline.QuietHoursConfig{
+				Mode:     pipeline.QuietModeDiscard,
+				Timezone: "Asia/Seoul",
+				Windows:  []pipeline.QuietWindowConfig{{Start: "22:00", End: "08:00"}},
+			},
+			want: map[string]pipeline.QuietHoursConfig{
+				"ops": {
+					Mode:     pipeline.QuietModeDigest,
+					Timezone: "Asia/Seoul",
+					Windows:  []pipeline.QuietWindowConfig{{Days: []string{"sat", "sun"}, Start: "00:00", End: "00:00"}},
+				},
+				"oncall": {},
+			},
+		},
+		{
+			name:            "crawl_overrides_mode_only",
+			crawlQuietHours: QuietHoursConfig{Mode: pipeline.QuietModeDigest},
+			wantDefault: pipeline.QuietHoursConfig{
+				Mode:     pipeline.QuietModeDigest,
+				Timezone: "Asia/Seoul",
+				Windows:  []pipeline.QuietWindowConfig{{Start: "22:00", End: "08:00"}},
+			},
+			want: map[string]pipeline.QuietHoursConfig{
+				"ops": {
+					Mode:     pipeline.QuietModeDigest,
+					Timezone: "Asia/Seoul",
+					Windows:  []pipeline.QuietWindowConfig{{Days: []string{"sat", "sun"}, Start: "00:00", End: "00:00"}},
+				},
+				"oncall": {},
+			},
+		},
+		{
+			name:            "crawl_disables",
+			crawlQuietHours: QuietHoursConfig{Enabled: &disabled},
+			wantDefault:     pipeline.QuietHoursConfig{},
+			want: map[string]pipeline.QuietHoursConfig{
+				"ops":    {},
+				"oncall": {},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDefault := alerts.QuietHours.overriddenBy(tt.crawlQuietHours).toPipelineConfig()
+			assert.Equal(t, tt.wantDefault, gotDefault)
+
+			got := alerts.receiverQuietHours(receivers, tt.crawlQuietHours)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCrawlHTTPTargetConfig_Validate_htmlFormat(t *testing.T) {
+	html := HTMLConfig{Fields: map[string]HTMLFieldConfig{"title": {Selector: "h1"}}}
+
+	tests := []struct {
+		name    string
+		format  format.Format
+		wantErr bool
+	}{
+		{
+			name:   "no_format",
+			format: "",
+		},
+		{
+			name:   "json_format",
+			format: format.FormatJSON,
+		},
+		{
+			name:    "xml_format",
+			format:  format.FormatXML,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := CrawlHTTPTargetConfig{
+				Method: "GET",
+				URL:    "https://example.com",
+				Format: tt.format,
+				HTML:   html,
+			}
+
+			err := cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCrawlTargetConfig_Validate_authWithSigning(t *testing.T) {
+	tests := []struct {
+		name        string
+		signingType signing.Type
+		wantErr     bool
+	}{
+		{
+			name:        "hmac",
+			signingType: signing.TypeHMAC,
+		},
+		{
+			name:        "sigv4",
+			signingType: signing.TypeSigV4,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := CrawlTargetConfig{
+				HTTP: CrawlHTTPTargetConfig{Method: "GET", URL: "https://example.com"},
+				Auth: AuthConfig{Type: auth.TypeBearer, Bearer: BearerAuthConfig{Token: "t0k"}},
+				Signing: SigningConfig{
+					Type: tt.signingType,
+					HMAC: HMACSigningConfig{Secret: "secret", StringToSign: "$BODY", SignatureHeader: "X-Signature"},
+					SigV4: SigV4SigningConfig{
+						AccessKeyID: "id", SecretAccessKey: "key", Region: "us-east-1", Service: "execute-api",
+					},
+				},
+			}
+
+			err := cfg.Validate()
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "sigv4")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
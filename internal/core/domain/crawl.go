@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 )
 
 type CrawlRequest struct {
@@ -9,9 +11,32 @@ type CrawlRequest struct {
 	Method string
 	Header http.Header
 	Body   []byte
+
+	// AllowErrorStatus makes responses with status >= 400 succeed instead of
+	// failing with HTTPStatusError.
+	AllowErrorStatus bool
 }
 
 type CrawlResponse struct {
-	Header http.Header
-	Body   []byte
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// URL is the final URL of the response after redirects.
+	URL string
+
+	// Duration is the time taken from sending the request to reading the
+	// whole response body.
+	Duration time.Duration
+}
+
+// HTTPStatusError is returned when the crawl target responds with an error
+// status.
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected http response code '%s'", e.Status)
 }
@@ -0,0 +1,10 @@
+package domain
+
+type Message struct {
+	// CrawlName is the name of the crawl which produced the message.
+	CrawlName string `json:"crawlName"`
+	Text      string `json:"text"`
+
+	// Variables are the variables used to render Text.
+	Variables map[string]string `json:"variables,omitempty"`
+}
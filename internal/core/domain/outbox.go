@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// OutboxMessage is a message which failed to be delivered and waits in outbox
+// to be retried.
+type OutboxMessage struct {
+	// ID is assigned by outbox in the order of push.
+	ID uint64 `json:"-"`
+
+	Message   Message   `json:"message"`
+	CreatedAt time.Time `json:"createdAt"`
+}
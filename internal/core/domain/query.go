@@ -1,6 +1,15 @@
 package domain
 
 type QueryResult struct {
-	Matched   bool
-	Variables map[string]string
+	Matched   bool              `json:"matched"`
+	Variables map[string]string `json:"variables,omitempty"`
+	Items     []QueryItem       `json:"-"`
+}
+
+// QueryItem is an element of a list in the crawl response.
+type QueryItem struct {
+	// Key is the identity of the item.
+	Key string
+	// Value is the item encoded in JSON.
+	Value string
 }
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// CrawlState is the state of a crawl which is kept across crawls and process
+// restarts.
+type CrawlState struct {
+	LastResponseHash string       `json:"lastResponseHash,omitempty"`
+	LastQueryResult  *QueryResult `json:"lastQueryResult,omitempty"`
+	LastMatchedAt    time.Time    `json:"lastMatchedAt"`
+	LastMessage      string       `json:"lastMessage,omitempty"`
+	LastSentAt       time.Time    `json:"lastSentAt"`
+
+	// SeenItems maps keys of items to the items seen in the previous crawl.
+	// It is nil if items were never collected.
+	SeenItems map[string]string `json:"seenItems"`
+
+	// Firing reports whether a message was sent for the matching check query,
+	// and the query has not stopped matching since then.
+	Firing bool `json:"firing"`
+
+	// SuppressedCount is the number of messages suppressed by cooldown since
+	// the last sent message.
+	SuppressedCount int `json:"suppressedCount"`
+
+	// PendingDigests are the messages held during quiet hours of receivers,
+	// which are sent as a digest when quiet hours end. They are keyed by
+	// receiver names.
+	PendingDigests map[string][]string `json:"pendingDigests,omitempty"`
+
+	// ConsecutiveFailures is the number of crawls failed in a row.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+
+	// CrawlFailing reports whether an error message was sent to any receiver
+	// for the current consecutive failures.
+	CrawlFailing bool `json:"crawlFailing"`
+}
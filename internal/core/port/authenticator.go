@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+// Authenticator adds credentials to crawl requests.
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *domain.CrawlRequest) error
+}
@@ -7,5 +7,7 @@ import (
 )
 
 type HTTPCrawler interface {
-	Crawl(context.Context, domain.CrawlRequest) (domain.CrawlResponse, error)
+	// Crawl sends req and returns its response. The request is signed with
+	// signer right before it is sent, if signer is not nil.
+	Crawl(ctx context.Context, req domain.CrawlRequest, signer RequestSigner) (domain.CrawlResponse, error)
 }
@@ -1,7 +1,15 @@
 package port
 
-import "context"
+import (
+	"context"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
 
 type MessageSender interface {
-	SendMessage(ctx context.Context, message string) error
+	SendMessage(ctx context.Context, message domain.Message) error
+
+	// Destination returns where messages are sent, such as a chat ID or a
+	// webhook URL. Undelivered messages are kept in outbox by destination.
+	Destination() string
 }
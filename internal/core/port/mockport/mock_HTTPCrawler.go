@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	domain "github.com/isutare412/crawlert/internal/core/domain"
+	port "github.com/isutare412/crawlert/internal/core/port"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -22,9 +23,9 @@ func (_m *MockHTTPCrawler) EXPECT() *MockHTTPCrawler_Expecter {
 	return &MockHTTPCrawler_Expecter{mock: &_m.Mock}
 }
 
-// Crawl provides a mock function with given fields: _a0, _a1
-func (_m *MockHTTPCrawler) Crawl(_a0 context.Context, _a1 domain.CrawlRequest) (domain.CrawlResponse, error) {
-	ret := _m.Called(_a0, _a1)
+// Crawl provides a mock function with given fields: ctx, req, signer
+func (_m *MockHTTPCrawler) Crawl(ctx context.Context, req domain.CrawlRequest, signer port.RequestSigner) (domain.CrawlResponse, error) {
+	ret := _m.Called(ctx, req, signer)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Crawl")
@@ -32,17 +33,17 @@ func (_m *MockHTTPCrawler) Crawl(_a0 context.Context, _a1 domain.CrawlRequest) (
 
 	var r0 domain.CrawlResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, domain.CrawlRequest) (domain.CrawlResponse, error)); ok {
-		return rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CrawlRequest, port.RequestSigner) (domain.CrawlResponse, error)); ok {
+		return rf(ctx, req, signer)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, domain.CrawlRequest) domain.CrawlResponse); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CrawlRequest, port.RequestSigner) domain.CrawlResponse); ok {
+		r0 = rf(ctx, req, signer)
 	} else {
 		r0 = ret.Get(0).(domain.CrawlResponse)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, domain.CrawlRequest) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.CrawlRequest, port.RequestSigner) error); ok {
+		r1 = rf(ctx, req, signer)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -56,15 +57,16 @@ type MockHTTPCrawler_Crawl_Call struct {
 }
 
 // Crawl is a helper method to define mock.On call
-//   - _a0 context.Context
-//   - _a1 domain.CrawlRequest
-func (_e *MockHTTPCrawler_Expecter) Crawl(_a0 interface{}, _a1 interface{}) *MockHTTPCrawler_Crawl_Call {
-	return &MockHTTPCrawler_Crawl_Call{Call: _e.mock.On("Crawl", _a0, _a1)}
+//   - ctx context.Context
+//   - req domain.CrawlRequest
+//   - signer port.RequestSigner
+func (_e *MockHTTPCrawler_Expecter) Crawl(ctx interface{}, req interface{}, signer interface{}) *MockHTTPCrawler_Crawl_Call {
+	return &MockHTTPCrawler_Crawl_Call{Call: _e.mock.On("Crawl", ctx, req, signer)}
 }
 
-func (_c *MockHTTPCrawler_Crawl_Call) Run(run func(_a0 context.Context, _a1 domain.CrawlRequest)) *MockHTTPCrawler_Crawl_Call {
+func (_c *MockHTTPCrawler_Crawl_Call) Run(run func(ctx context.Context, req domain.CrawlRequest, signer port.RequestSigner)) *MockHTTPCrawler_Crawl_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(domain.CrawlRequest))
+		run(args[0].(context.Context), args[1].(domain.CrawlRequest), args[2].(port.RequestSigner))
 	})
 	return _c
 }
@@ -74,7 +76,7 @@ func (_c *MockHTTPCrawler_Crawl_Call) Return(_a0 domain.CrawlResponse, _a1 error
 	return _c
 }
 
-func (_c *MockHTTPCrawler_Crawl_Call) RunAndReturn(run func(context.Context, domain.CrawlRequest) (domain.CrawlResponse, error)) *MockHTTPCrawler_Crawl_Call {
+func (_c *MockHTTPCrawler_Crawl_Call) RunAndReturn(run func(context.Context, domain.CrawlRequest, port.RequestSigner) (domain.CrawlResponse, error)) *MockHTTPCrawler_Crawl_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -5,6 +5,7 @@ package mockport
 import (
 	context "context"
 
+	domain "github.com/isutare412/crawlert/internal/core/domain"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -21,8 +22,53 @@ func (_m *MockMessageSender) EXPECT() *MockMessageSender_Expecter {
 	return &MockMessageSender_Expecter{mock: &_m.Mock}
 }
 
+// Destination provides a mock function with no fields
+func (_m *MockMessageSender) Destination() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Destination")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// MockMessageSender_Destination_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Destination'
+type MockMessageSender_Destination_Call struct {
+	*mock.Call
+}
+
+// Destination is a helper method to define mock.On call
+func (_e *MockMessageSender_Expecter) Destination() *MockMessageSender_Destination_Call {
+	return &MockMessageSender_Destination_Call{Call: _e.mock.On("Destination")}
+}
+
+func (_c *MockMessageSender_Destination_Call) Run(run func()) *MockMessageSender_Destination_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockMessageSender_Destination_Call) Return(_a0 string) *MockMessageSender_Destination_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMessageSender_Destination_Call) RunAndReturn(run func() string) *MockMessageSender_Destination_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SendMessage provides a mock function with given fields: ctx, message
-func (_m *MockMessageSender) SendMessage(ctx context.Context, message string) error {
+func (_m *MockMessageSender) SendMessage(ctx context.Context, message domain.Message) error {
 	ret := _m.Called(ctx, message)
 
 	if len(ret) == 0 {
@@ -30,7 +76,7 @@ func (_m *MockMessageSender) SendMessage(ctx context.Context, message string) er
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Message) error); ok {
 		r0 = rf(ctx, message)
 	} else {
 		r0 = ret.Error(0)
@@ -46,14 +92,14 @@ type MockMessageSender_SendMessage_Call struct {
 
 // SendMessage is a helper method to define mock.On call
 //   - ctx context.Context
-//   - message string
+//   - message domain.Message
 func (_e *MockMessageSender_Expecter) SendMessage(ctx interface{}, message interface{}) *MockMessageSender_SendMessage_Call {
 	return &MockMessageSender_SendMessage_Call{Call: _e.mock.On("SendMessage", ctx, message)}
 }
 
-func (_c *MockMessageSender_SendMessage_Call) Run(run func(ctx context.Context, message string)) *MockMessageSender_SendMessage_Call {
+func (_c *MockMessageSender_SendMessage_Call) Run(run func(ctx context.Context, message domain.Message)) *MockMessageSender_SendMessage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(domain.Message))
 	})
 	return _c
 }
@@ -63,7 +109,7 @@ func (_c *MockMessageSender_SendMessage_Call) Return(_a0 error) *MockMessageSend
 	return _c
 }
 
-func (_c *MockMessageSender_SendMessage_Call) RunAndReturn(run func(context.Context, string) error) *MockMessageSender_SendMessage_Call {
+func (_c *MockMessageSender_SendMessage_Call) RunAndReturn(run func(context.Context, domain.Message) error) *MockMessageSender_SendMessage_Call {
 	_c.Call.Return(run)
 	return _c
 }
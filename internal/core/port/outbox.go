@@ -0,0 +1,21 @@
+package port
+
+import (
+	"context"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+// Outbox keeps undelivered messages per message sender until they are
+// delivered.
+type Outbox interface {
+	PushOutboxMessage(ctx context.Context, senderKey string, message domain.OutboxMessage) error
+
+	// PeekOutboxMessage returns the oldest message of the sender. ok is false
+	// if there is no message.
+	PeekOutboxMessage(ctx context.Context, senderKey string) (message domain.OutboxMessage, ok bool, err error)
+	DeleteOutboxMessage(ctx context.Context, senderKey string, id uint64) error
+
+	// CountOutboxMessages returns the number of messages of each sender.
+	CountOutboxMessages(ctx context.Context) (map[string]int, error)
+}
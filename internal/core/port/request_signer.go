@@ -0,0 +1,10 @@
+package port
+
+import "net/http"
+
+// RequestSigner adds signatures to HTTP requests, such as HMAC over method,
+// path, timestamp and body. body is the request body, which is already set
+// to req.
+type RequestSigner interface {
+	SignRequest(req *http.Request, body []byte) error
+}
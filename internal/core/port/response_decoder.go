@@ -0,0 +1,9 @@
+package port
+
+import "github.com/isutare412/crawlert/internal/core/domain"
+
+// ResponseDecoder decodes a crawled response body into JSON, to which queries
+// are applied.
+type ResponseDecoder interface {
+	DecodeResponse(resp domain.CrawlResponse) ([]byte, error)
+}
@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+type StateStore interface {
+	// GetCrawlState returns the state of the crawl. Zero value is returned if
+	// the state was never put.
+	GetCrawlState(ctx context.Context, crawlName string) (domain.CrawlState, error)
+	PutCrawlState(ctx context.Context, crawlName string, state domain.CrawlState) error
+}
@@ -0,0 +1,60 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata" // embed timezone database for minimal container images
+
+	cronlib "github.com/robfig/cron/v3"
+)
+
+var parser = cronlib.NewParser(
+	cronlib.SecondOptional |
+		cronlib.Minute |
+		cronlib.Hour |
+		cronlib.Dom |
+		cronlib.Month |
+		cronlib.Dow |
+		cronlib.Descriptor,
+)
+
+// Schedule computes fire times of a cron expression.
+type Schedule struct {
+	schedule cronlib.Schedule
+	location *time.Location
+}
+
+// Parse parses a standard 5-field cron expression, or a 6-field one with
+// leading seconds field. Fire times are computed in timezone, which is an IANA
+// timezone name like "Asia/Seoul". Local timezone is used if timezone is
+// empty. Expressions which never fire, such as "0 0 30 2 *", are rejected.
+func Parse(expr, timezone string) (*Schedule, error) {
+	location := time.Local
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("loading timezone %s: %w", timezone, err)
+		}
+		location = loc
+	}
+
+	schedule, err := parser.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing cron expression: %w", err)
+	}
+
+	s := &Schedule{
+		schedule: schedule,
+		location: location,
+	}
+	if s.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never fires", expr)
+	}
+	return s, nil
+}
+
+// Next returns the first fire time after t. It returns zero time if there is
+// no fire time within five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	return s.schedule.Next(t.In(s.location))
+}
@@ -0,0 +1,86 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	type args struct {
+		expr     string
+		timezone string
+		t        time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "weekday_morning_in_timezone",
+			args: args{
+				expr:     "0 9 * * MON-FRI",
+				timezone: "Asia/Seoul",
+				t:        time.Date(2024, 10, 4, 1, 0, 0, 0, time.UTC), // Fri 10:00 KST
+			},
+			want: time.Date(2024, 10, 7, 0, 0, 0, 0, time.UTC), // Mon 09:00 KST
+		},
+		{
+			name: "with_seconds_field",
+			args: args{
+				expr:     "30 */5 * * * *",
+				timezone: "UTC",
+				t:        time.Date(2024, 10, 4, 1, 0, 0, 0, time.UTC),
+			},
+			want: time.Date(2024, 10, 4, 1, 0, 30, 0, time.UTC),
+		},
+		{
+			name: "descriptor",
+			args: args{
+				expr:     "@daily",
+				timezone: "UTC",
+				t:        time.Date(2024, 10, 4, 1, 0, 0, 0, time.UTC),
+			},
+			want: time.Date(2024, 10, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "invalid_expression",
+			args: args{
+				expr: "* * *",
+			},
+			wantErr: true,
+		},
+		{
+			name: "never_fires",
+			args: args{
+				expr: "0 0 30 2 *",
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown_timezone",
+			args: args{
+				expr:     "0 9 * * *",
+				timezone: "Mars/Olympus",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.args.expr, tt.args.timezone)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(s.Next(tt.args.t)), "want %v, got %v", tt.want, s.Next(tt.args.t))
+		})
+	}
+}
@@ -1,5 +1,8 @@
 package discord
 
+import "github.com/isutare412/crawlert/internal/retry"
+
 type MessageSenderConfig struct {
 	WebhookURL string
+	Retry      retry.Policy
 }
@@ -4,6 +4,10 @@ type webhookRequest struct {
 	Content string `json:"content"`
 }
 
+type rateLimitResponse struct {
+	RetryAfter float64 `json:"retry_after"`
+}
+
 // splitMessage splits a message into chunks that fit within Discord's
 // 2000 character limit, preferring to split at newline boundaries.
 func splitMessage(msg string) []string {
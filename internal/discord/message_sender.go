@@ -7,6 +7,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/retry"
 )
 
 const maxMessageLength = 2000
@@ -14,7 +19,8 @@ const maxMessageLength = 2000
 type MessageSender struct {
 	httpClient *http.Client
 
-	webhookURL string
+	webhookURL  string
+	retryPolicy retry.Policy
 }
 
 func NewMessageSender(cfg MessageSenderConfig) *MessageSender {
@@ -22,19 +28,34 @@ func NewMessageSender(cfg MessageSenderConfig) *MessageSender {
 	transport.MaxIdleConnsPerHost = 100
 
 	return &MessageSender{
-		httpClient: &http.Client{Transport: transport},
-		webhookURL: cfg.WebhookURL,
+		httpClient:  &http.Client{Transport: transport},
+		webhookURL:  cfg.WebhookURL,
+		retryPolicy: cfg.Retry,
 	}
 }
 
-func (s *MessageSender) SendMessage(ctx context.Context, message string) error {
-	if len(message) == 0 {
+// Destination returns the webhook URL to which messages are sent.
+func (s *MessageSender) Destination() string {
+	return s.webhookURL
+}
+
+func (s *MessageSender) SendMessage(ctx context.Context, message domain.Message) error {
+	if len(message.Text) == 0 {
 		return nil
 	}
 
-	chunks := splitMessage(message)
+	chunks := splitMessage(message.Text)
+	var wait time.Duration
 	for i, chunk := range chunks {
-		if err := s.sendChunk(ctx, chunk); err != nil {
+		if err := sleep(ctx, wait); err != nil {
+			return fmt.Errorf("waiting for rate limit reset: %w", err)
+		}
+
+		err := retry.Do(ctx, s.retryPolicy, func(ctx context.Context) (err error) {
+			wait, err = s.sendChunk(ctx, chunk)
+			return err
+		})
+		if err != nil {
 			return fmt.Errorf("sending chunk %d/%d: %w", i+1, len(chunks), err)
 		}
 	}
@@ -42,33 +63,78 @@ func (s *MessageSender) SendMessage(ctx context.Context, message string) error {
 	return nil
 }
 
-func (s *MessageSender) sendChunk(ctx context.Context, content string) error {
+// sendChunk sends content and returns the delay until the rate limit resets if
+// the rate limit is exhausted.
+func (s *MessageSender) sendChunk(ctx context.Context, content string) (time.Duration, error) {
 	reqBody, err := json.Marshal(webhookRequest{Content: content})
 	if err != nil {
-		return fmt.Errorf("marshaling message body: %w", err)
+		return 0, fmt.Errorf("marshaling message body: %w", err)
 	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.webhookURL, bytes.NewReader(reqBody))
 	if err != nil {
-		return fmt.Errorf("building http request: %w", err)
+		return 0, fmt.Errorf("building http request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	httpResp, err := s.httpClient.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("doing http request: %w", err)
+		return 0, retry.HTTPError(fmt.Errorf("doing http request: %w", err), nil)
 	}
 	defer httpResp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(httpResp.Body)
 	if err != nil {
-		return fmt.Errorf("reading response body: %w", err)
+		return 0, fmt.Errorf("reading response body: %w", err)
 	}
 
 	if httpResp.StatusCode != http.StatusNoContent && httpResp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected response status code %s; body (%s)", httpResp.Status, string(bodyBytes))
+		err := fmt.Errorf("unexpected response status code %s; body (%s)", httpResp.Status, string(bodyBytes))
+		if httpResp.StatusCode == http.StatusTooManyRequests {
+			return 0, retry.Retryable(err, rateLimitResetAfter(httpResp.Header, bodyBytes))
+		}
+		return 0, retry.HTTPError(err, httpResp)
 	}
 
-	return nil
+	if httpResp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return rateLimitResetAfter(httpResp.Header, nil), nil
+	}
+	return 0, nil
+}
+
+// rateLimitResetAfter returns the delay until the rate limit resets. Discord
+// reports it by Retry-After header and retry_after field of body on 429
+// responses, and by X-RateLimit-Reset-After header on every response.
+// https://discord.com/developers/docs/topics/rate-limits
+func rateLimitResetAfter(header http.Header, body []byte) time.Duration {
+	if after, ok := retry.ParseRetryAfter(header.Get("Retry-After"), time.Now()); ok {
+		return after
+	}
+
+	var resp rateLimitResponse
+	if err := json.Unmarshal(body, &resp); err == nil && resp.RetryAfter > 0 {
+		return time.Duration(resp.RetryAfter * float64(time.Second))
+	}
+
+	if seconds, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
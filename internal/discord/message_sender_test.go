@@ -0,0 +1,135 @@
+package discord
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/retry"
+)
+
+func TestMessageSender_SendMessage(t *testing.T) {
+	policy := retry.Policy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Second, // not below delays requested in tests
+	}
+
+	type response struct {
+		status int
+		header map[string]string
+		body   string
+	}
+	tests := []struct {
+		name         string
+		text         string
+		responses    []response
+		wantAttempts int
+		wantMinDelay time.Duration
+		wantErr      bool
+	}{
+		{
+			name: "success",
+			text: "hello",
+			responses: []response{
+				{status: http.StatusNoContent},
+			},
+			wantAttempts: 1,
+		},
+		{
+			name: "retry_after_header",
+			text: "hello",
+			responses: []response{
+				{status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "0.2"}},
+				{status: http.StatusNoContent},
+			},
+			wantAttempts: 2,
+			wantMinDelay: 200 * time.Millisecond,
+		},
+		{
+			name: "retry_after_body",
+			text: "hello",
+			responses: []response{
+				{status: http.StatusTooManyRequests, body: `{"message":"You are being rate limited.","retry_after":0.2,"global":false}`},
+				{status: http.StatusNoContent},
+			},
+			wantAttempts: 2,
+			wantMinDelay: 200 * time.Millisecond,
+		},
+		{
+			name: "wait_for_exhausted_rate_limit_between_chunks",
+			text: strings.Repeat("a", maxMessageLength) + "\n" + "b",
+			responses: []response{
+				{status: http.StatusNoContent, header: map[string]string{
+					"X-RateLimit-Remaining":   "0",
+					"X-RateLimit-Reset-After": "0.2",
+				}},
+				{status: http.StatusNoContent},
+			},
+			wantAttempts: 2,
+			wantMinDelay: 200 * time.Millisecond,
+		},
+		{
+			name: "give_up_after_attempts",
+			text: "hello",
+			responses: []response{
+				{status: http.StatusServiceUnavailable},
+				{status: http.StatusServiceUnavailable},
+				{status: http.StatusServiceUnavailable},
+			},
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name: "no_retry_on_not_found",
+			text: "hello",
+			responses: []response{
+				{status: http.StatusNotFound, body: `{"message":"Unknown Webhook","code":10015}`},
+			},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			var attemptTimes []time.Time
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				resp := tt.responses[attempts]
+				attempts++
+				attemptTimes = append(attemptTimes, time.Now())
+
+				for k, v := range resp.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(resp.status)
+				w.Write([]byte(resp.body))
+			}))
+			defer server.Close()
+
+			sender := NewMessageSender(MessageSenderConfig{
+				WebhookURL: server.URL,
+				Retry:      policy,
+			})
+
+			err := sender.SendMessage(context.Background(), domain.Message{Text: tt.text})
+			assert.Equal(t, tt.wantAttempts, attempts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			if tt.wantMinDelay > 0 {
+				assert.GreaterOrEqual(t, attemptTimes[1].Sub(attemptTimes[0]), tt.wantMinDelay)
+			}
+		})
+	}
+}
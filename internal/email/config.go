@@ -0,0 +1,62 @@
+package email
+
+import (
+	"fmt"
+
+	"github.com/isutare412/crawlert/internal/retry"
+)
+
+type MessageSenderConfig struct {
+	Host     string
+	Port     int
+	Security Security
+	Auth     AuthMechanism
+	Username string
+	Password string
+	From     string
+	To       []string
+
+	// Subject is a template of the mail subject. $CRAWL_NAME, ${CRAWL_NAME}
+	// is substituted to the name of the crawl.
+	Subject string
+
+	// Retry is applied to network failures and transient replies of the SMTP
+	// server.
+	Retry retry.Policy
+}
+
+// Security is how the connection to SMTP server is secured.
+type Security string
+
+const (
+	SecurityNone     Security = "none"
+	SecuritySTARTTLS Security = "starttls"
+	SecurityTLS      Security = "tls"
+)
+
+func (s Security) Validate() error {
+	switch s {
+	case SecurityNone, SecuritySTARTTLS, SecurityTLS:
+		return nil
+	default:
+		return fmt.Errorf("unknown security '%s'", s)
+	}
+}
+
+// AuthMechanism is the SMTP authentication mechanism. Empty mechanism means no
+// authentication.
+type AuthMechanism string
+
+const (
+	AuthPlain AuthMechanism = "plain"
+	AuthLogin AuthMechanism = "login"
+)
+
+func (m AuthMechanism) Validate() error {
+	switch m {
+	case "", AuthPlain, AuthLogin:
+		return nil
+	default:
+		return fmt.Errorf("unknown auth mechanism '%s'", m)
+	}
+}
@@ -0,0 +1,52 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"strings"
+	"time"
+)
+
+const defaultSubject = "[crawlert] $CRAWL_NAME"
+
+func buildSubject(template, crawlName string) string {
+	if template == "" {
+		template = defaultSubject
+	}
+
+	return strings.NewReplacer(
+		"${CRAWL_NAME}", crawlName,
+		"$CRAWL_NAME", crawlName,
+	).Replace(template)
+}
+
+// buildMail builds a plain text mail in RFC 5322 format.
+func buildMail(from string, to []string, subject, body string, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	headers := [][2]string{
+		{"From", from},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", date.Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `text/plain; charset="utf-8"`},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", h[0], h[1])
+	}
+	buf.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := w.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n"))); err != nil {
+		return nil, fmt.Errorf("encoding body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("closing body encoder: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,228 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/retry"
+)
+
+const defaultTimeout = 30 * time.Second
+
+type MessageSender struct {
+	tlsConfig *tls.Config
+
+	host     string
+	port     int
+	security Security
+	auth     AuthMechanism
+	username string
+	password string
+	from     string
+	to       []string
+	subject  string
+
+	retryPolicy retry.Policy
+
+	// envelopeFrom and envelopeTo are the bare addresses of from and to, which
+	// may have display names.
+	envelopeFrom string
+	envelopeTo   []string
+}
+
+func NewMessageSender(cfg MessageSenderConfig) (*MessageSender, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from address: %w", err)
+	}
+
+	envelopeTo := make([]string, 0, len(cfg.To))
+	for _, to := range cfg.To {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			return nil, fmt.Errorf("parsing to address %q: %w", to, err)
+		}
+		envelopeTo = append(envelopeTo, addr.Address)
+	}
+
+	return &MessageSender{
+		tlsConfig: &tls.Config{ServerName: cfg.Host},
+		host:      cfg.Host,
+		port:      cfg.Port,
+		security:  cfg.Security,
+		auth:      cfg.Auth,
+		username:  cfg.Username,
+		password:  cfg.Password,
+		from:      cfg.From,
+		to:        cfg.To,
+		subject:   cfg.Subject,
+
+		retryPolicy: cfg.Retry,
+
+		envelopeFrom: from.Address,
+		envelopeTo:   envelopeTo,
+	}, nil
+}
+
+// Destination returns the SMTP server and recipients to which mails are sent.
+func (s *MessageSender) Destination() string {
+	return net.JoinHostPort(s.host, strconv.Itoa(s.port)) + " " + strings.Join(s.envelopeTo, ",")
+}
+
+func (s *MessageSender) SendMessage(ctx context.Context, message domain.Message) error {
+	if len(message.Text) == 0 {
+		return nil
+	}
+
+	subject := buildSubject(s.subject, message.CrawlName)
+	mail, err := buildMail(s.from, s.to, subject, message.Text, time.Now())
+	if err != nil {
+		return fmt.Errorf("building mail: %w", err)
+	}
+
+	return retry.Do(ctx, s.retryPolicy, func(ctx context.Context) error {
+		return smtpError(s.connectAndSend(ctx, mail))
+	})
+}
+
+func (s *MessageSender) connectAndSend(ctx context.Context, mail []byte) error {
+	client, err := s.connect(ctx)
+	if err != nil {
+		return fmt.Errorf("connecting to smtp server: %w", err)
+	}
+	defer client.Close()
+
+	if err := s.send(client, mail); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// smtpError marks err as retryable if it is caused by network failure or a
+// transient reply of the SMTP server, whose code is 4xx.
+func smtpError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		if protoErr.Code >= 400 && protoErr.Code < 500 {
+			return retry.Retryable(err, 0)
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return retry.Retryable(err, 0)
+	}
+	return err
+}
+
+func (s *MessageSender) connect(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	switch s.security {
+	case SecurityTLS:
+		dialer := tls.Dialer{Config: s.tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	default:
+		dialer := net.Dialer{}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dialing %s: %w", addr, err)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("setting deadline: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("creating smtp client: %w", err)
+	}
+
+	if s.security == SecuritySTARTTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, fmt.Errorf("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(s.tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("starting tls: %w", err)
+		}
+	}
+
+	if auth := s.smtpAuth(); auth != nil {
+		if err := client.Auth(auth); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("authenticating: %w", err)
+		}
+	}
+
+	return client, nil
+}
+
+func (s *MessageSender) send(client *smtp.Client, mail []byte) error {
+	if err := client.Mail(s.envelopeFrom); err != nil {
+		return fmt.Errorf("setting sender: %w", err)
+	}
+	for _, to := range s.envelopeTo {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("adding recipient %s: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("starting data: %w", err)
+	}
+	if _, err := w.Write(mail); err != nil {
+		return fmt.Errorf("writing mail: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("finishing data: %w", err)
+	}
+
+	if err := client.Quit(); err != nil {
+		return fmt.Errorf("quitting: %w", err)
+	}
+	return nil
+}
+
+func (s *MessageSender) smtpAuth() smtp.Auth {
+	switch s.auth {
+	case AuthPlain:
+		return smtp.PlainAuth("", s.username, s.password, s.host)
+	case AuthLogin:
+		return &loginAuth{username: s.username, password: s.password, host: s.host}
+	default:
+		return nil
+	}
+}
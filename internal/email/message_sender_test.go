@@ -0,0 +1,263 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/retry"
+)
+
+// fakeSMTPServer is a minimal SMTP server which records received mails.
+type fakeSMTPServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+
+	mu         sync.Mutex
+	failures   int // number of transient failures to reply to MAIL FROM
+	auths      []string
+	from       string
+	recipients []string
+	data       string
+}
+
+func newFakeSMTPServer(t *testing.T, implicitTLS bool) (server *fakeSMTPServer, clientTLS *tls.Config) {
+	// Borrow a self-signed certificate from httptest.
+	tlsServer := httptest.NewTLSServer(nil)
+	t.Cleanup(tlsServer.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(tlsServer.Certificate())
+	clientTLS = &tls.Config{RootCAs: roots, ServerName: "127.0.0.1"}
+	serverTLS := &tls.Config{Certificates: tlsServer.TLS.Certificates}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	if implicitTLS {
+		listener = tls.NewListener(listener, serverTLS)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server = &fakeSMTPServer{listener: listener, tlsConfig: serverTLS}
+	go server.serve()
+	return server, clientTLS
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(lines ...string) {
+		for _, l := range lines {
+			conn.Write([]byte(l + "\r\n"))
+		}
+	}
+	readLine := func() (string, bool) {
+		line, err := r.ReadString('\n')
+		return strings.TrimRight(line, "\r\n"), err == nil
+	}
+
+	reply("220 localhost ESMTP")
+	for {
+		line, ok := readLine()
+		if !ok {
+			return
+		}
+
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"):
+			reply("250-localhost", "250-STARTTLS", "250 AUTH PLAIN LOGIN")
+		case cmd == "STARTTLS":
+			reply("220 ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			conn = tlsConn
+			r = bufio.NewReader(conn)
+		case strings.HasPrefix(cmd, "AUTH PLAIN"):
+			decoded, _ := base64.StdEncoding.DecodeString(strings.Fields(line)[2])
+			s.recordAuth("PLAIN " + strings.ReplaceAll(string(decoded), "\x00", " "))
+			reply("235 authenticated")
+		case strings.HasPrefix(cmd, "AUTH LOGIN"):
+			reply("334 " + base64.StdEncoding.EncodeToString([]byte("Username:")))
+			user, _ := readLine()
+			reply("334 " + base64.StdEncoding.EncodeToString([]byte("Password:")))
+			pass, _ := readLine()
+			u, _ := base64.StdEncoding.DecodeString(user)
+			p, _ := base64.StdEncoding.DecodeString(pass)
+			s.recordAuth("LOGIN " + string(u) + " " + string(p))
+			reply("235 authenticated")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.mu.Lock()
+			if s.failures > 0 {
+				s.failures--
+				s.mu.Unlock()
+				reply("451 try again later")
+				continue
+			}
+			s.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			s.mu.Unlock()
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.mu.Lock()
+			s.recipients = append(s.recipients, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, ok := readLine()
+				if !ok || l == "." {
+					break
+				}
+				data.WriteString(l + "\n")
+			}
+			s.mu.Lock()
+			s.data = data.String()
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) recordAuth(auth string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auths = append(s.auths, auth)
+}
+
+func TestMessageSender_SendMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		security Security
+		auth     AuthMechanism
+		wantAuth []string
+	}{
+		{
+			name:     "no_security_with_plain_auth",
+			security: SecurityNone,
+			auth:     AuthPlain,
+			wantAuth: []string{"PLAIN  tester secret"},
+		},
+		{
+			name:     "starttls_with_login_auth",
+			security: SecuritySTARTTLS,
+			auth:     AuthLogin,
+			wantAuth: []string{"LOGIN tester secret"},
+		},
+		{
+			name:     "implicit_tls_without_auth",
+			security: SecurityTLS,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, clientTLS := newFakeSMTPServer(t, tt.security == SecurityTLS)
+
+			sender, err := NewMessageSender(MessageSenderConfig{
+				Host:     "127.0.0.1",
+				Port:     server.port(),
+				Security: tt.security,
+				Auth:     tt.auth,
+				Username: "tester",
+				Password: "secret",
+				From:     "Crawlert <crawlert@example.com>",
+				To:       []string{"alice@example.com", "Bob <bob@example.com>"},
+				Subject:  "[alert] ${CRAWL_NAME}",
+			})
+			require.NoError(t, err)
+			sender.tlsConfig = clientTLS
+
+			err = sender.SendMessage(context.Background(), domain.Message{
+				CrawlName: "판교수영장",
+				Text:      "Found titles.\nfoo=bar",
+			})
+			require.NoError(t, err)
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+
+			assert.Equal(t, tt.wantAuth, server.auths)
+			assert.Equal(t, "crawlert@example.com", server.from)
+			assert.Equal(t, []string{"alice@example.com", "bob@example.com"}, server.recipients)
+			assert.Contains(t, server.data, "From: Crawlert <crawlert@example.com>\n")
+			assert.Contains(t, server.data, "To: alice@example.com, Bob <bob@example.com>\n")
+			assert.Contains(t, server.data, "Subject: =?utf-8?q?[alert]_=ED=8C=90=EA=B5=90=EC=88=98=EC=98=81=EC=9E=A5?=\n")
+			assert.Contains(t, server.data, "Found titles.\nfoo=3Dbar\n")
+		})
+	}
+}
+
+func TestMessageSender_SendMessage_unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	sender, err := NewMessageSender(MessageSenderConfig{
+		Host:     "127.0.0.1",
+		Port:     port,
+		Security: SecurityNone,
+		From:     "crawlert@example.com",
+		To:       []string{"alice@example.com"},
+	})
+	require.NoError(t, err)
+
+	err = sender.SendMessage(context.Background(), domain.Message{CrawlName: "test", Text: "hello"})
+	assert.ErrorContains(t, err, "127.0.0.1:"+strconv.Itoa(port))
+}
+
+func TestMessageSender_SendMessage_retry(t *testing.T) {
+	server, _ := newFakeSMTPServer(t, false)
+	server.failures = 1
+
+	sender, err := NewMessageSender(MessageSenderConfig{
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		Security: SecurityNone,
+		From:     "crawlert@example.com",
+		To:       []string{"alice@example.com"},
+		Retry:    retry.Policy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+	})
+	require.NoError(t, err)
+
+	err = sender.SendMessage(context.Background(), domain.Message{CrawlName: "test", Text: "hello"})
+	require.NoError(t, err)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Equal(t, "crawlert@example.com", server.from)
+}
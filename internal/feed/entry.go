@@ -0,0 +1,48 @@
+package feed
+
+// Entry is a normalized entry of RSS and Atom feeds.
+type Entry struct {
+	// ID is the identity of the entry. It falls back to the link, and then the
+	// title if the feed does not provide one.
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Link  string `json:"link"`
+
+	// Published is formatted as RFC 3339 if the date of the feed is parsable,
+	// or as it is otherwise.
+	Published string `json:"published"`
+	Summary   string `json:"summary"`
+}
+
+type rssDocument struct {
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	GUID        string `xml:"guid"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description"`
+}
+
+type atomDocument struct {
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Links     []atomLink `xml:"link"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Summary   string     `xml:"summary"`
+	Content   string     `xml:"content"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
@@ -0,0 +1,161 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+// dateLayouts are layouts of dates found in feeds, tried in order.
+var dateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+}
+
+// Parser parses RSS 2.0 and Atom feeds into a JSON array of entries.
+type Parser struct{}
+
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+func (p *Parser) DecodeResponse(resp domain.CrawlResponse) ([]byte, error) {
+	entries, err := parseEntries(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := json.Marshal(entries)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling entries: %w", err)
+	}
+	return result, nil
+}
+
+func parseEntries(body []byte) ([]Entry, error) {
+	root, err := rootElement(body)
+	if err != nil {
+		return nil, fmt.Errorf("finding root element: %w", err)
+	}
+
+	switch root {
+	case "rss":
+		var doc rssDocument
+		if err := newXMLDecoder(body).Decode(&doc); err != nil {
+			return nil, fmt.Errorf("unmarshaling rss: %w", err)
+		}
+		return rssEntries(doc), nil
+	case "feed":
+		var doc atomDocument
+		if err := newXMLDecoder(body).Decode(&doc); err != nil {
+			return nil, fmt.Errorf("unmarshaling atom: %w", err)
+		}
+		return atomEntries(doc), nil
+	default:
+		return nil, fmt.Errorf("unknown feed root element <%s>", root)
+	}
+}
+
+// newXMLDecoder returns a decoder which supports encodings other than UTF-8,
+// which are common in feeds.
+func newXMLDecoder(body []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = charset.NewReaderLabel
+	return decoder
+}
+
+func rootElement(body []byte) (string, error) {
+	decoder := newXMLDecoder(body)
+	for {
+		token, err := decoder.Token()
+		switch {
+		case errors.Is(err, io.EOF):
+			return "", fmt.Errorf("no element found")
+		case err != nil:
+			return "", fmt.Errorf("reading xml token: %w", err)
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func rssEntries(doc rssDocument) []Entry {
+	entries := make([]Entry, 0, len(doc.Channel.Items))
+	for _, item := range doc.Channel.Items {
+		entries = append(entries, newEntry(item.GUID, item.Title, item.Link, item.PubDate, item.Description))
+	}
+	return entries
+}
+
+func atomEntries(doc atomDocument) []Entry {
+	entries := make([]Entry, 0, len(doc.Entries))
+	for _, entry := range doc.Entries {
+		published := entry.Published
+		if published == "" {
+			published = entry.Updated
+		}
+
+		summary := entry.Summary
+		if summary == "" {
+			summary = entry.Content
+		}
+
+		entries = append(entries, newEntry(entry.ID, entry.Title, atomLinkOf(entry.Links), published, summary))
+	}
+	return entries
+}
+
+// atomLinkOf returns the alternate link of an Atom entry.
+func atomLinkOf(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
+
+func newEntry(id, title, link, published, summary string) Entry {
+	entry := Entry{
+		ID:        strings.TrimSpace(id),
+		Title:     strings.TrimSpace(title),
+		Link:      strings.TrimSpace(link),
+		Published: normalizeDate(strings.TrimSpace(published)),
+		Summary:   strings.TrimSpace(summary),
+	}
+
+	if entry.ID == "" {
+		entry.ID = entry.Link
+	}
+	if entry.ID == "" {
+		entry.ID = entry.Title
+	}
+	return entry
+}
+
+func normalizeDate(date string) string {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	return date
+}
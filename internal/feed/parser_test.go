@@ -0,0 +1,100 @@
+package feed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+func TestParser_DecodeResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "rss",
+			body: `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Blog</title>
+    <item>
+      <guid>https://blog.example.com/?p=2</guid>
+      <title>Second post</title>
+      <link>https://blog.example.com/second</link>
+      <pubDate>Tue, 01 Oct 2024 09:00:00 +0900</pubDate>
+      <description><![CDATA[<p>Hello again</p>]]></description>
+    </item>
+    <item>
+      <title>First post</title>
+      <link>https://blog.example.com/first</link>
+      <pubDate>sometime</pubDate>
+    </item>
+  </channel>
+</rss>`,
+			want: `[
+  {"id":"https://blog.example.com/?p=2","title":"Second post","link":"https://blog.example.com/second",
+   "published":"2024-10-01T00:00:00Z","summary":"<p>Hello again</p>"},
+  {"id":"https://blog.example.com/first","title":"First post","link":"https://blog.example.com/first",
+   "published":"sometime","summary":""}
+]`,
+		},
+		{
+			name: "atom",
+			body: `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Releases</title>
+  <entry>
+    <id>tag:github.com,2008:Repository/1/v1.2.0</id>
+    <title>v1.2.0</title>
+    <link rel="self" href="https://example.com/self"/>
+    <link rel="alternate" href="https://github.com/foo/bar/releases/tag/v1.2.0"/>
+    <updated>2024-10-01T09:00:00+09:00</updated>
+    <content type="html">Bug fixes</content>
+  </entry>
+</feed>`,
+			want: `[
+  {"id":"tag:github.com,2008:Repository/1/v1.2.0","title":"v1.2.0",
+   "link":"https://github.com/foo/bar/releases/tag/v1.2.0","published":"2024-10-01T00:00:00Z","summary":"Bug fixes"}
+]`,
+		},
+		{
+			name: "non_utf8_encoding",
+			body: "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n" +
+				"<rss version=\"2.0\"><channel><item><guid>1</guid><title>Caf\xe9</title></item></channel></rss>",
+			want: `[{"id":"1","title":"Café","link":"","published":"","summary":""}]`,
+		},
+		{
+			name: "empty_feed",
+			body: `<rss version="2.0"><channel><title>Empty</title></channel></rss>`,
+			want: `[]`,
+		},
+		{
+			name:    "not_a_feed",
+			body:    `<html><body>hello</body></html>`,
+			wantErr: true,
+		},
+		{
+			name:    "json",
+			body:    `{"items":[]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewParser().DecodeResponse(domain.CrawlResponse{Body: []byte(tt.body)})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
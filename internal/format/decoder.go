@@ -0,0 +1,164 @@
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+// Decoder decodes response bodies of a format into JSON.
+type Decoder struct {
+	format Format
+}
+
+func NewDecoder(format Format) *Decoder {
+	return &Decoder{
+		format: format,
+	}
+}
+
+func (d *Decoder) DecodeResponse(resp domain.CrawlResponse) ([]byte, error) {
+	format := d.format
+	if format == FormatAuto {
+		format = detectFormat(resp.Header.Get("Content-Type"))
+	}
+
+	switch format {
+	case FormatXML:
+		return decodeXML(resp.Body)
+	case FormatYAML:
+		return decodeYAML(resp.Body)
+	case FormatCSV:
+		return decodeCSV(resp.Body)
+	case FormatText:
+		return decodeText(resp.Body)
+	case FormatNDJSON:
+		return decodeNDJSON(resp.Body)
+	default:
+		return resp.Body, nil
+	}
+}
+
+// decodeYAML decodes a YAML document. Keys of mappings are converted to
+// strings.
+func decodeYAML(body []byte) ([]byte, error) {
+	var value any
+	if err := yaml.Unmarshal(body, &value); err != nil {
+		return nil, fmt.Errorf("unmarshaling yaml: %w", err)
+	}
+
+	result, err := json.Marshal(stringifyKeys(value))
+	if err != nil {
+		return nil, fmt.Errorf("marshaling into json: %w", err)
+	}
+	return result, nil
+}
+
+func stringifyKeys(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, elem := range v {
+			v[key] = stringifyKeys(elem)
+		}
+		return v
+	case map[any]any:
+		converted := make(map[string]any, len(v))
+		for key, elem := range v {
+			converted[fmt.Sprint(key)] = stringifyKeys(elem)
+		}
+		return converted
+	case []any:
+		for i, elem := range v {
+			v[i] = stringifyKeys(elem)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// decodeCSV decodes CSV into an array of objects keyed by the header row.
+func decodeCSV(body []byte) ([]byte, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	switch {
+	case errors.Is(err, io.EOF):
+		return []byte("[]"), nil
+	case err != nil:
+		return nil, fmt.Errorf("reading csv header: %w", err)
+	}
+
+	rows := make([]map[string]string, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading csv record: %w", err)
+		}
+
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				row[name] = record[i]
+			} else {
+				row[name] = ""
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	result, err := json.Marshal(rows)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling into json: %w", err)
+	}
+	return result, nil
+}
+
+// decodeText decodes plain text into a JSON string.
+func decodeText(body []byte) ([]byte, error) {
+	result, err := json.Marshal(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("marshaling into json: %w", err)
+	}
+	return result, nil
+}
+
+// decodeNDJSON decodes newline delimited JSON documents into an array. Blank
+// lines are ignored.
+func decodeNDJSON(body []byte) ([]byte, error) {
+	values := make([]json.RawMessage, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(nil, len(body)+1)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		if !json.Valid(text) {
+			return nil, fmt.Errorf("line %d is not a valid json", line)
+		}
+		values = append(values, json.RawMessage(bytes.Clone(text)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanning lines: %w", err)
+	}
+
+	result, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling into json: %w", err)
+	}
+	return result, nil
+}
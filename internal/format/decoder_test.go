@@ -0,0 +1,137 @@
+package format
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+func TestDecoder_DecodeResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      Format
+		contentType string
+		body        string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:   "json",
+			format: FormatJSON,
+			body:   `{"a":1}`,
+			want:   `{"a":1}`,
+		},
+		{
+			name:   "xml",
+			format: FormatXML,
+			body: `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <stock symbol="ACME" currency="USD">
+      <price>12.5</price>
+      <tag>a</tag>
+      <tag>b</tag>
+      <note lang="en">cheap</note>
+    </stock>
+  </soap:Body>
+</soap:Envelope>`,
+			want: `{"Envelope":{"Body":{"stock":{"@symbol":"ACME","@currency":"USD","price":"12.5","tag":["a","b"],` +
+				`"note":{"@lang":"en","#text":"cheap"}}}}}`,
+		},
+		{
+			name:   "yaml",
+			format: FormatYAML,
+			body: `
+version: 2
+services:
+  - name: web
+    ports: [80, 443]
+1: numeric key
+`,
+			want: `{"version":2,"services":[{"name":"web","ports":[80,443]}],"1":"numeric key"}`,
+		},
+		{
+			name:   "csv",
+			format: FormatCSV,
+			body:   "name,price\nkeyboard,50\n\"mouse, wireless\",20\nmonitor\n",
+			want: `[{"name":"keyboard","price":"50"},{"name":"mouse, wireless","price":"20"},` +
+				`{"name":"monitor","price":""}]`,
+		},
+		{
+			name:   "empty_csv",
+			format: FormatCSV,
+			body:   "",
+			want:   `[]`,
+		},
+		{
+			name:   "text",
+			format: FormatText,
+			body:   "status: \"ok\"\n",
+			want:   `"status: \"ok\"\n"`,
+		},
+		{
+			name:   "ndjson",
+			format: FormatNDJSON,
+			body:   "{\"level\":\"info\"}\n\n{\"level\":\"error\"}\n",
+			want:   `[{"level":"info"},{"level":"error"}]`,
+		},
+		{
+			name:    "invalid_ndjson",
+			format:  FormatNDJSON,
+			body:    "{\"level\":\"info\"}\nbroken\n",
+			wantErr: true,
+		},
+		{
+			name:        "auto_xml",
+			format:      FormatAuto,
+			contentType: "application/rss+xml; charset=utf-8",
+			body:        `<rss><title>foo</title></rss>`,
+			want:        `{"rss":{"title":"foo"}}`,
+		},
+		{
+			name:        "auto_csv",
+			format:      FormatAuto,
+			contentType: "text/csv",
+			body:        "a\n1\n",
+			want:        `[{"a":"1"}]`,
+		},
+		{
+			name:        "auto_text",
+			format:      FormatAuto,
+			contentType: "text/plain",
+			body:        "OK",
+			want:        `"OK"`,
+		},
+		{
+			name:   "auto_fallback_to_json",
+			format: FormatAuto,
+			body:   `[1,2]`,
+			want:   `[1,2]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := domain.CrawlResponse{
+				Header: http.Header{},
+				Body:   []byte(tt.body),
+			}
+			if tt.contentType != "" {
+				resp.Header.Set("Content-Type", tt.contentType)
+			}
+
+			got, err := NewDecoder(tt.format).DecodeResponse(resp)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
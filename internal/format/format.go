@@ -0,0 +1,64 @@
+package format
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// Format is the format of response bodies.
+type Format string
+
+const (
+	// FormatAuto detects the format from Content-Type header, falling back to
+	// JSON.
+	FormatAuto Format = "auto"
+	// FormatJSON is a JSON document.
+	FormatJSON Format = "json"
+	// FormatXML is an XML document.
+	FormatXML Format = "xml"
+	// FormatYAML is a YAML document.
+	FormatYAML Format = "yaml"
+	// FormatCSV is a CSV with header row.
+	FormatCSV Format = "csv"
+	// FormatText is plain text.
+	FormatText Format = "text"
+	// FormatNDJSON is newline delimited JSON documents.
+	FormatNDJSON Format = "ndjson"
+)
+
+func (f Format) Validate() error {
+	switch f {
+	case "", FormatAuto, FormatJSON, FormatXML, FormatYAML, FormatCSV, FormatText, FormatNDJSON:
+		return nil
+	default:
+		return fmt.Errorf("unknown format '%s'", f)
+	}
+}
+
+// detectFormat returns the format of the media type of contentType.
+func detectFormat(contentType string) Format {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return FormatJSON
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return FormatJSON
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return FormatXML
+	case mediaType == "application/yaml" || mediaType == "application/x-yaml" || mediaType == "text/yaml" ||
+		mediaType == "text/x-yaml":
+		return FormatYAML
+	case mediaType == "text/csv":
+		return FormatCSV
+	case mediaType == "application/x-ndjson" || mediaType == "application/ndjson" ||
+		mediaType == "application/jsonl" || mediaType == "application/x-jsonlines":
+		return FormatNDJSON
+	case strings.HasPrefix(mediaType, "text/"):
+		return FormatText
+	default:
+		return FormatJSON
+	}
+}
@@ -0,0 +1,120 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// xmlElement is an element being decoded.
+type xmlElement struct {
+	name     string
+	attrs    []xml.Attr
+	children []xmlChild
+	text     strings.Builder
+}
+
+type xmlChild struct {
+	name  string
+	value any
+}
+
+// decodeXML decodes an XML document into a JSON object keyed by the name of
+// the root element. An element becomes its text if it has neither attributes
+// nor child elements. Otherwise it becomes an object, where attributes are
+// keyed by "@" prefixed names, text by "#text", and child elements by their
+// names. Child elements with the same name are collected into an array.
+// Namespaces are ignored.
+func decodeXML(body []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	var (
+		stack []*xmlElement
+		root  *xmlChild
+	)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading xml token: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, &xmlElement{name: t.Name.Local, attrs: t.Attr})
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		case xml.EndElement:
+			elem := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			child := xmlChild{name: elem.name, value: elem.value()}
+			if len(stack) == 0 {
+				root = &child
+				continue
+			}
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, child)
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no root element found")
+	}
+
+	result, err := json.Marshal(map[string]any{root.name: root.value})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling into json: %w", err)
+	}
+	return result, nil
+}
+
+func (e *xmlElement) value() any {
+	text := strings.TrimSpace(e.text.String())
+	if len(e.attrs) == 0 && len(e.children) == 0 {
+		return text
+	}
+
+	obj := make(map[string]any, len(e.attrs)+len(e.children)+1)
+	for _, attr := range e.attrs {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		obj["@"+attr.Name.Local] = attr.Value
+	}
+	if text != "" {
+		obj["#text"] = text
+	}
+
+	for _, child := range e.children {
+		existing, ok := obj[child.name]
+		switch {
+		case !ok:
+			obj[child.name] = child.value
+		case isXMLArray(existing):
+			obj[child.name] = append(existing.(xmlArray), child.value)
+		default:
+			obj[child.name] = xmlArray{existing, child.value}
+		}
+	}
+	return obj
+}
+
+// xmlArray is an array of child elements with the same name. It is
+// distinguished from other values to collect repeated elements.
+type xmlArray []any
+
+func isXMLArray(v any) bool {
+	_, ok := v.(xmlArray)
+	return ok
+}
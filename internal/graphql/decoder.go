@@ -0,0 +1,54 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+// ResponseError is returned if a GraphQL response has errors.
+type ResponseError struct {
+	Messages []string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("graphql errors: %s", strings.Join(e.Messages, "; "))
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Decoder unwraps data of GraphQL responses.
+type Decoder struct{}
+
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// DecodeResponse returns data of the GraphQL response. [*ResponseError] is
+// returned if the response has errors, even if partial data exists.
+func (d *Decoder) DecodeResponse(resp domain.CrawlResponse) ([]byte, error) {
+	var gqlResp response
+	if err := json.Unmarshal(resp.Body, &gqlResp); err != nil {
+		return nil, fmt.Errorf("unmarshaling graphql response: %w", err)
+	}
+
+	if len(gqlResp.Errors) > 0 {
+		messages := make([]string, 0, len(gqlResp.Errors))
+		for _, e := range gqlResp.Errors {
+			messages = append(messages, e.Message)
+		}
+		return nil, &ResponseError{Messages: messages}
+	}
+
+	if len(gqlResp.Data) == 0 {
+		return []byte("null"), nil
+	}
+	return gqlResp.Data, nil
+}
@@ -0,0 +1,70 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+func TestDecoder_DecodeResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "data_is_unwrapped",
+			body: `{"data":{"repository":{"stargazerCount":42}}}`,
+			want: `{"repository":{"stargazerCount":42}}`,
+		},
+		{
+			name: "missing_data",
+			body: `{}`,
+			want: `null`,
+		},
+		{
+			name:    "errors_with_partial_data",
+			body:    `{"data":{"a":1},"errors":[{"message":"field b not found"},{"message":"rate limited"}]}`,
+			wantErr: "graphql errors: field b not found; rate limited",
+		},
+		{
+			name:    "invalid_json",
+			body:    `<html>bad gateway</html>`,
+			wantErr: "unmarshaling graphql response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDecoder().DecodeResponse(domain.CrawlResponse{Body: []byte(tt.body)})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestBuildRequestBody(t *testing.T) {
+	got, err := BuildRequestBody(
+		"query Repo($owner: String!) { repository(owner: $owner) { stargazerCount } }",
+		map[string]any{"owner": "golang"},
+		"Repo")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"query": "query Repo($owner: String!) { repository(owner: $owner) { stargazerCount } }",
+		"variables": {"owner": "golang"},
+		"operationName": "Repo"
+	}`, string(got))
+
+	got, err = BuildRequestBody("{ viewer { login } }", nil, "")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"query": "{ viewer { login } }"}`, string(got))
+}
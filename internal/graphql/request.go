@@ -0,0 +1,27 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// request is the body of GraphQL requests over HTTP.
+type request struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
+}
+
+// BuildRequestBody returns the JSON body of a GraphQL request to be sent with
+// POST.
+func BuildRequestBody(query string, variables map[string]any, operationName string) ([]byte, error) {
+	body, err := json.Marshal(request{
+		Query:         query,
+		Variables:     variables,
+		OperationName: operationName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling graphql request: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,26 @@
+package html
+
+type ExtractorConfig struct {
+	// Fields are extractions of which results make up the keys of the
+	// extracted JSON object.
+	Fields map[string]FieldConfig
+}
+
+type FieldConfig struct {
+	// Selector is a CSS selector of elements. It selects from the parent
+	// element if the field is nested, or from the whole document otherwise.
+	// Empty selector selects the parent element itself.
+	Selector string
+
+	// Attribute is the name of the attribute to extract. Text content of the
+	// element is extracted if empty.
+	Attribute string
+
+	// All extracts every selected element into an array. Only the first one
+	// is extracted otherwise.
+	All bool
+
+	// Fields are nested extractions applied to each selected element, which
+	// produce an object instead of text.
+	Fields map[string]FieldConfig
+}
@@ -0,0 +1,147 @@
+package html
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+// Extractor extracts a JSON object from an HTML document with CSS selectors.
+type Extractor struct {
+	fields map[string]*field
+}
+
+type field struct {
+	selector  cascadia.Sel // nil selects the parent element itself
+	attribute string
+	all       bool
+	fields    map[string]*field
+}
+
+func NewExtractor(cfg ExtractorConfig) (*Extractor, error) {
+	fields, err := compileFields(cfg.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Extractor{
+		fields: fields,
+	}, nil
+}
+
+func (e *Extractor) DecodeResponse(resp domain.CrawlResponse) ([]byte, error) {
+	doc, err := html.Parse(bytes.NewReader(resp.Body))
+	if err != nil {
+		return nil, fmt.Errorf("parsing html: %w", err)
+	}
+
+	result, err := json.Marshal(extractFields(doc, e.fields))
+	if err != nil {
+		return nil, fmt.Errorf("marshaling extracted fields: %w", err)
+	}
+	return result, nil
+}
+
+func compileFields(cfgs map[string]FieldConfig) (map[string]*field, error) {
+	fields := make(map[string]*field, len(cfgs))
+	for name, cfg := range cfgs {
+		var sel cascadia.Sel
+		if cfg.Selector != "" {
+			parsed, err := cascadia.Parse(cfg.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("parsing selector of field %s: %w", name, err)
+			}
+			sel = parsed
+		}
+
+		nested, err := compileFields(cfg.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("compiling fields of %s: %w", name, err)
+		}
+
+		fields[name] = &field{
+			selector:  sel,
+			attribute: cfg.Attribute,
+			all:       cfg.All,
+			fields:    nested,
+		}
+	}
+	return fields, nil
+}
+
+func extractFields(node *html.Node, fields map[string]*field) map[string]any {
+	result := make(map[string]any, len(fields))
+	for name, f := range fields {
+		result[name] = f.extract(node)
+	}
+	return result
+}
+
+// extract returns the value of the first selected element, or values of all
+// selected elements. nil is returned if no element is selected and all is
+// false.
+func (f *field) extract(node *html.Node) any {
+	if f.selector == nil {
+		if f.all {
+			return []any{f.value(node)}
+		}
+		return f.value(node)
+	}
+
+	if !f.all {
+		selected := cascadia.Query(node, f.selector)
+		if selected == nil {
+			return nil
+		}
+		return f.value(selected)
+	}
+
+	selected := cascadia.QueryAll(node, f.selector)
+	values := make([]any, 0, len(selected))
+	for _, n := range selected {
+		values = append(values, f.value(n))
+	}
+	return values
+}
+
+func (f *field) value(node *html.Node) any {
+	switch {
+	case len(f.fields) > 0:
+		return extractFields(node, f.fields)
+	case f.attribute != "":
+		for _, attr := range node.Attr {
+			if attr.Key == f.attribute {
+				return attr.Val
+			}
+		}
+		return nil
+	default:
+		return textContent(node)
+	}
+}
+
+// textContent returns text of node and its descendants, with consecutive
+// whitespaces collapsed into a single space.
+func textContent(node *html.Node) string {
+	var sb strings.Builder
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+			return
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			collect(child)
+		}
+	}
+	collect(node)
+
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
@@ -0,0 +1,96 @@
+package html
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+const testDocument = `
+<html>
+<body>
+  <h1 class="title">
+    Weekly   <em>deals</em>
+  </h1>
+  <ul>
+    <li class="product" data-id="p1">
+      <a class="name" href="/products/1">Keyboard</a>
+      <span class="price">$50</span>
+    </li>
+    <li class="product" data-id="p2">
+      <a class="name" href="/products/2">Mouse</a>
+      <span class="price">$20</span>
+    </li>
+  </ul>
+</body>
+</html>
+`
+
+func TestExtractor_DecodeResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields map[string]FieldConfig
+		want   string
+	}{
+		{
+			name: "first_text",
+			fields: map[string]FieldConfig{
+				"title": {Selector: "h1.title"},
+				"price": {Selector: ".price"},
+			},
+			want: `{"title":"Weekly deals","price":"$50"}`,
+		},
+		{
+			name: "all_attributes",
+			fields: map[string]FieldConfig{
+				"links": {Selector: "a.name", Attribute: "href", All: true},
+			},
+			want: `{"links":["/products/1","/products/2"]}`,
+		},
+		{
+			name: "nested_fields",
+			fields: map[string]FieldConfig{
+				"products": {
+					Selector: "li.product",
+					All:      true,
+					Fields: map[string]FieldConfig{
+						"id":    {Attribute: "data-id"},
+						"name":  {Selector: ".name"},
+						"price": {Selector: ".price"},
+					},
+				},
+			},
+			want: `{"products":[{"id":"p1","name":"Keyboard","price":"$50"},{"id":"p2","name":"Mouse","price":"$20"}]}`,
+		},
+		{
+			name: "not_found",
+			fields: map[string]FieldConfig{
+				"first": {Selector: ".unknown"},
+				"all":   {Selector: ".unknown", All: true},
+				"attr":  {Selector: "h1", Attribute: "href"},
+			},
+			want: `{"first":null,"all":[],"attr":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractor, err := NewExtractor(ExtractorConfig{Fields: tt.fields})
+			require.NoError(t, err)
+
+			got, err := extractor.DecodeResponse(domain.CrawlResponse{Body: []byte(testDocument)})
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestNewExtractor_invalidSelector(t *testing.T) {
+	_, err := NewExtractor(ExtractorConfig{Fields: map[string]FieldConfig{
+		"broken": {Selector: "li["},
+	}})
+	assert.Error(t, err)
+}
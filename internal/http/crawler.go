@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/core/port"
 )
 
 type Crawler struct {
@@ -23,7 +25,11 @@ func NewCrawler() *Crawler {
 	}
 }
 
-func (c *Crawler) Crawl(ctx context.Context, req domain.CrawlRequest) (domain.CrawlResponse, error) {
+func (c *Crawler) Crawl(
+	ctx context.Context,
+	req domain.CrawlRequest,
+	signer port.RequestSigner,
+) (domain.CrawlResponse, error) {
 	bodyBuffer := bytes.NewBuffer(req.Body)
 	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, bodyBuffer)
 	if err != nil {
@@ -36,14 +42,24 @@ func (c *Crawler) Crawl(ctx context.Context, req domain.CrawlRequest) (domain.Cr
 		}
 	}
 
+	if signer != nil {
+		if err := signer.SignRequest(httpReq, req.Body); err != nil {
+			return domain.CrawlResponse{}, fmt.Errorf("signing http request: %w", err)
+		}
+	}
+
+	start := time.Now()
 	httpResp, err := c.client.Do(httpReq)
 	if err != nil {
 		return domain.CrawlResponse{}, fmt.Errorf("doing http request: %w", err)
 	}
 	defer httpResp.Body.Close()
 
-	if httpResp.StatusCode >= http.StatusBadRequest {
-		return domain.CrawlResponse{}, fmt.Errorf("unexpected http response code '%s'", httpResp.Status)
+	if httpResp.StatusCode >= http.StatusBadRequest && !req.AllowErrorStatus {
+		return domain.CrawlResponse{}, &domain.HTTPStatusError{
+			StatusCode: httpResp.StatusCode,
+			Status:     httpResp.Status,
+		}
 	}
 
 	bodyBytes, err := io.ReadAll(httpResp.Body)
@@ -52,7 +68,10 @@ func (c *Crawler) Crawl(ctx context.Context, req domain.CrawlRequest) (domain.Cr
 	}
 
 	return domain.CrawlResponse{
-		Header: httpResp.Header.Clone(),
-		Body:   bodyBytes,
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header.Clone(),
+		Body:       bodyBytes,
+		URL:        httpResp.Request.URL.String(),
+		Duration:   time.Since(start),
 	}, nil
 }
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+// Store keeps data of crawlert in memory. Data is lost when the process exits.
+type Store struct {
+	mu          sync.Mutex
+	crawlStates map[string]domain.CrawlState
+	outbox      map[string][]domain.OutboxMessage
+	outboxSeq   uint64
+}
+
+func NewStore() *Store {
+	return &Store{
+		crawlStates: make(map[string]domain.CrawlState),
+		outbox:      make(map[string][]domain.OutboxMessage),
+	}
+}
+
+func (s *Store) Close() error {
+	return nil
+}
+
+func (s *Store) GetCrawlState(ctx context.Context, crawlName string) (domain.CrawlState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.crawlStates[crawlName], nil
+}
+
+func (s *Store) PutCrawlState(ctx context.Context, crawlName string, state domain.CrawlState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.crawlStates[crawlName] = state
+	return nil
+}
+
+func (s *Store) PushOutboxMessage(ctx context.Context, senderKey string, message domain.OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.outboxSeq++
+	message.ID = s.outboxSeq
+	s.outbox[senderKey] = append(s.outbox[senderKey], message)
+	return nil
+}
+
+func (s *Store) PeekOutboxMessage(
+	ctx context.Context,
+	senderKey string,
+) (message domain.OutboxMessage, ok bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := s.outbox[senderKey]
+	if len(messages) == 0 {
+		return domain.OutboxMessage{}, false, nil
+	}
+	return messages[0], true, nil
+}
+
+func (s *Store) DeleteOutboxMessage(ctx context.Context, senderKey string, id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := s.outbox[senderKey]
+	for i, m := range messages {
+		if m.ID == id {
+			s.outbox[senderKey] = append(messages[:i:i], messages[i+1:]...)
+			break
+		}
+	}
+	if len(s.outbox[senderKey]) == 0 {
+		delete(s.outbox, senderKey)
+	}
+	return nil
+}
+
+func (s *Store) CountOutboxMessages(ctx context.Context) (map[string]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[string]int, len(s.outbox))
+	for senderKey, messages := range s.outbox {
+		counts[senderKey] = len(messages)
+	}
+	return counts, nil
+}
@@ -1,24 +1,132 @@
 package pipeline
 
 import (
+	"fmt"
 	"time"
+
+	"github.com/isutare412/crawlert/internal/auth"
+	"github.com/isutare412/crawlert/internal/format"
+	"github.com/isutare412/crawlert/internal/html"
+	"github.com/isutare412/crawlert/internal/signing"
 )
 
 type ProcessorConfig struct {
 	Crawls []CrawlConfig
+	Outbox OutboxConfig
+}
+
+type OutboxConfig struct {
+	// RelayInterval is the interval to retry delivery of messages in outbox.
+	RelayInterval time.Duration
+
+	// MaxAge is the age after which undelivered messages are dropped.
+	MaxAge time.Duration
 }
 
 type CrawlConfig struct {
-	Name     string
-	Enabled  bool
-	Interval time.Duration
-	Target   CrawlTargetConfig
-	Query    CrawlQueryConfig
-	Message  string
+	Name           string
+	Enabled        bool
+	Interval       time.Duration
+	Schedule       string
+	Timezone       string
+	Target         CrawlTargetConfig
+	Query          CrawlQueryConfig
+	Message        string
+	ResolveMessage string
+	NotifyOn       NotifyMode
+	Cooldown       time.Duration
+
+	// QuietHours applies to receivers not in ReceiverQuietHours.
+	QuietHours QuietHoursConfig
+
+	// Receivers are names of receivers to which messages of the crawl are
+	// sent.
+	Receivers []string
+
+	// ReceiverQuietHours are quiet hours of receivers, keyed by receiver
+	// names.
+	ReceiverQuietHours map[string]QuietHoursConfig
+
+	OnError OnErrorConfig
 }
 
+// OnErrorConfig decides how crawl failures are alerted. Failures are not
+// alerted if Message is empty.
+type OnErrorConfig struct {
+	// Threshold is the number of consecutive failures to send Message.
+	Threshold int
+
+	Message         string
+	RecoveryMessage string
+}
+
+// CrawlTargetConfig is the target of a crawl. Only one of the targets is set.
 type CrawlTargetConfig struct {
-	HTTP CrawlHTTPTargetConfig
+	HTTP    CrawlHTTPTargetConfig
+	Feed    CrawlFeedTargetConfig
+	GraphQL CrawlGraphQLTargetConfig
+	Steps   []CrawlStepConfig
+
+	// Auth adds credentials to every request of the target.
+	Auth auth.AuthenticatorConfig
+
+	// Signing signs every request of the target.
+	Signing signing.SignerConfig
+}
+
+// CrawlStepConfig is a request of a multi-step target. URL, header values and
+// body are templates of variables extracted by earlier steps. Queries are
+// applied to a JSON object which maps step names to their response bodies.
+type CrawlStepConfig struct {
+	Name   string
+	Method string
+	URL    string
+	Header map[string]string
+	Body   string
+
+	// Extract are jq queries applied to the response body of the step, of
+	// which results are exposed as variables to later steps.
+	Extract map[string]string
+
+	// ForEach is the name of a variable holding a JSON array. The step is
+	// requested for each item of the array, exposed as $ITEM, and its result
+	// is the array of response bodies.
+	ForEach string
+}
+
+// CrawlFeedTargetConfig is an RSS or Atom feed, which is parsed into a JSON
+// array of entries {id, title, link, published, summary}.
+type CrawlFeedTargetConfig struct {
+	URL    string
+	Header map[string]string
+}
+
+// CrawlGraphQLTargetConfig is a GraphQL endpoint, which is requested with POST.
+// Queries are applied to data of responses, and responses with errors are
+// treated as crawl failures.
+type CrawlGraphQLTargetConfig struct {
+	Endpoint      string
+	Query         string
+	Variables     map[string]any
+	OperationName string
+	Header        map[string]string
+}
+
+// Entries of feed targets are collected by these queries unless items query is
+// set, so that only new entries are alerted. FeedEnvelopeItemsQuery is used
+// instead of FeedItemsQuery if query input is envelope.
+const (
+	FeedItemsQuery         = "."
+	FeedEnvelopeItemsQuery = ".body"
+	FeedKeyQuery           = ".id"
+)
+
+// FeedQueries returns the items and key queries of feed targets for input.
+func FeedQueries(input QueryInput) (items, key string) {
+	if input == QueryInputEnvelope {
+		return FeedEnvelopeItemsQuery, FeedKeyQuery
+	}
+	return FeedItemsQuery, FeedKeyQuery
 }
 
 type CrawlHTTPTargetConfig struct {
@@ -26,9 +134,138 @@ type CrawlHTTPTargetConfig struct {
 	URL    string
 	Header map[string]string
 	Body   string
+
+	// Format is the format of the response body, which is decoded into JSON
+	// unless it is JSON. JSON is assumed if empty.
+	Format format.Format
+
+	// HTML extracts JSON from the HTML response body if fields are set.
+	HTML html.ExtractorConfig
+
+	// Pagination crawls every page of the target if strategy is set.
+	Pagination PaginationConfig
+}
+
+// PaginationConfig decides how pages of a target are crawled. Items of the
+// pages, after responses are decoded, are merged into a single JSON array to
+// which queries are applied.
+type PaginationConfig struct {
+	Strategy PaginationStrategy
+
+	// Param is the query parameter of the page number, offset or cursor.
+	Param string
+
+	// Start is the first page number or offset.
+	Start int
+
+	// LimitParam is the query parameter of Limit, which is the page size of
+	// offset pagination. Offsets advance by the number of items of each page
+	// if Limit is zero.
+	LimitParam string
+	Limit      int
+
+	// Cursor is a jq query of the cursor of the next page. Pages are exhausted
+	// if it produces null, false or empty string.
+	Cursor string
+
+	// Items is a jq query of the array of items of each page.
+	Items string
+
+	// MaxPages is the maximum number of pages crawled per trigger.
+	MaxPages int
+}
+
+// PaginationStrategy decides how the next page is requested.
+type PaginationStrategy string
+
+const (
+	// PaginationPage requests incrementing page numbers until an empty page.
+	PaginationPage PaginationStrategy = "page"
+	// PaginationOffset requests incrementing offsets until an empty page, or
+	// a page with fewer items than limit.
+	PaginationOffset PaginationStrategy = "offset"
+	// PaginationCursor requests with the cursor found in the previous page.
+	PaginationCursor PaginationStrategy = "cursor"
+	// PaginationLink requests the URL of rel="next" in Link headers.
+	PaginationLink PaginationStrategy = "link"
+)
+
+func (s PaginationStrategy) Validate() error {
+	switch s {
+	case "", PaginationPage, PaginationOffset, PaginationCursor, PaginationLink:
+		return nil
+	default:
+		return fmt.Errorf("unknown pagination strategy '%s'", s)
+	}
 }
 
 type CrawlQueryConfig struct {
+	Mode      QueryMode
+	Input     QueryInput
 	Check     string
 	Variables map[string]string
+	Items     string
+	Key       string
+}
+
+// QueryMode decides the language of queries of a crawl.
+type QueryMode string
+
+const (
+	// QueryModeJQ applies jq queries to JSON.
+	QueryModeJQ QueryMode = "jq"
+	// QueryModeRegex applies regular expressions to the response body as text.
+	// Named capture groups of the check regex become variables, and items are
+	// not supported.
+	QueryModeRegex QueryMode = "regex"
+)
+
+func (m QueryMode) Validate() error {
+	switch m {
+	case "", QueryModeJQ, QueryModeRegex:
+		return nil
+	default:
+		return fmt.Errorf("unknown query mode '%s'", m)
+	}
+}
+
+// QueryInput decides what queries of a crawl are applied to.
+type QueryInput string
+
+const (
+	// QueryInputBody applies queries to the response body.
+	QueryInputBody QueryInput = "body"
+	// QueryInputEnvelope applies queries to an envelope of the response, which
+	// is {status, headers, body, duration_ms, url}. Responses with status
+	// >= 400 are not treated as crawl failures.
+	QueryInputEnvelope QueryInput = "envelope"
+)
+
+func (i QueryInput) Validate() error {
+	switch i {
+	case "", QueryInputBody, QueryInputEnvelope:
+		return nil
+	default:
+		return fmt.Errorf("unknown query input '%s'", i)
+	}
+}
+
+// NotifyMode decides which query results of a crawl are sent as messages.
+type NotifyMode string
+
+const (
+	// NotifyOnMatch sends a message every time the check query matches.
+	NotifyOnMatch NotifyMode = "match"
+	// NotifyOnChange sends a message only if the check query matches and the
+	// result differs from the one of the previous crawl.
+	NotifyOnChange NotifyMode = "change"
+)
+
+func (m NotifyMode) Validate() error {
+	switch m {
+	case "", NotifyOnMatch, NotifyOnChange:
+		return nil
+	default:
+		return fmt.Errorf("unknown notify mode '%s'", m)
+	}
 }
@@ -13,14 +13,33 @@ import (
 
 type crawlWorker struct {
 	httpCrawler    port.HTTPCrawler
+	decoder        port.ResponseDecoder
+	paginator      *paginator
+	workflow       *workflow
+	authenticator  port.Authenticator
+	signer         port.RequestSigner
 	triggerOutputs <-chan triggerOutput
 	crawlOutputs   chan<- crawlOutput
 	wg             sync.WaitGroup
 }
 
-func newCrawlWorker(httpCrawler port.HTTPCrawler, triggerOutputs <-chan triggerOutput, crawlOutputs chan<- crawlOutput) *crawlWorker {
+func newCrawlWorker(
+	httpCrawler port.HTTPCrawler,
+	decoder port.ResponseDecoder,
+	paginator *paginator,
+	workflow *workflow,
+	authenticator port.Authenticator,
+	signer port.RequestSigner,
+	triggerOutputs <-chan triggerOutput,
+	crawlOutputs chan<- crawlOutput,
+) *crawlWorker {
 	return &crawlWorker{
 		httpCrawler:    httpCrawler,
+		decoder:        decoder,
+		paginator:      paginator,
+		workflow:       workflow,
+		authenticator:  authenticator,
+		signer:         signer,
 		triggerOutputs: triggerOutputs,
 		crawlOutputs:   crawlOutputs,
 		wg:             sync.WaitGroup{},
@@ -39,12 +58,12 @@ func (w *crawlWorker) run() {
 			resp, err := w.crawl(ctx, output.crawlRequest)
 			if err != nil {
 				slog.ErrorContext(ctx, "failed to crawl", "error", err)
-				continue
 			}
 
 			w.crawlOutputs <- crawlOutput{
 				ctx:           ctx,
 				crawlResponse: resp,
+				err:           err,
 			}
 		}
 	}()
@@ -54,11 +73,36 @@ func (w *crawlWorker) shutdown() {
 	w.wg.Wait()
 }
 
+// crawl crawls req, or steps of the workflow instead if the target has steps.
 func (w *crawlWorker) crawl(ctx context.Context, req domain.CrawlRequest) (domain.CrawlResponse, error) {
-	resp, err := w.httpCrawler.Crawl(ctx, req)
+	if w.workflow != nil {
+		return w.workflow.run(ctx, w.crawlPage)
+	}
+	if w.paginator != nil {
+		return w.paginator.crawl(ctx, req, w.crawlPage)
+	}
+	return w.crawlPage(ctx, req)
+}
+
+// crawlPage crawls a single response and decodes it.
+func (w *crawlWorker) crawlPage(ctx context.Context, req domain.CrawlRequest) (domain.CrawlResponse, error) {
+	if w.authenticator != nil {
+		if err := w.authenticator.Authenticate(ctx, &req); err != nil {
+			return domain.CrawlResponse{}, fmt.Errorf("authenticating request: %w", err)
+		}
+	}
+	resp, err := w.httpCrawler.Crawl(ctx, req, w.signer)
 	if err != nil {
 		return domain.CrawlResponse{}, fmt.Errorf("crawling http: %w", err)
 	}
 
+	if w.decoder != nil {
+		decoded, err := w.decoder.DecodeResponse(resp)
+		if err != nil {
+			return domain.CrawlResponse{}, fmt.Errorf("decoding response: %w", err)
+		}
+		resp.Body = decoded
+	}
+
 	return resp, nil
 }
@@ -0,0 +1,38 @@
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/isutare412/crawlert/internal/core/port"
+	"github.com/isutare412/crawlert/internal/feed"
+	"github.com/isutare412/crawlert/internal/format"
+	"github.com/isutare412/crawlert/internal/graphql"
+	"github.com/isutare412/crawlert/internal/html"
+)
+
+// newResponseDecoder returns the decoder of responses of the target. nil is
+// returned if the response body is JSON to be queried as it is.
+func newResponseDecoder(cfg CrawlTargetConfig) (port.ResponseDecoder, error) {
+	if cfg.Feed.URL != "" {
+		return feed.NewParser(), nil
+	}
+
+	if cfg.GraphQL.Endpoint != "" {
+		return graphql.NewDecoder(), nil
+	}
+
+	if len(cfg.HTTP.HTML.Fields) > 0 {
+		extractor, err := html.NewExtractor(cfg.HTTP.HTML)
+		if err != nil {
+			return nil, fmt.Errorf("creating html extractor: %w", err)
+		}
+		return extractor, nil
+	}
+
+	switch cfg.HTTP.Format {
+	case "", format.FormatJSON:
+		return nil, nil
+	default:
+		return format.NewDecoder(cfg.HTTP.Format), nil
+	}
+}
@@ -0,0 +1,52 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+// responseEnvelope is the input of queries if query input is envelope.
+type responseEnvelope struct {
+	Status int `json:"status"`
+
+	// Headers maps lowercase header names to their values joined by ", ".
+	Headers map[string]string `json:"headers"`
+
+	// Body is the response body as JSON if it is a valid JSON, or as a JSON
+	// string otherwise.
+	Body json.RawMessage `json:"body"`
+
+	DurationMS int64  `json:"duration_ms"`
+	URL        string `json:"url"`
+}
+
+func buildEnvelope(resp domain.CrawlResponse) ([]byte, error) {
+	headers := make(map[string]string, len(resp.Header))
+	for name, values := range resp.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+
+	body := json.RawMessage(resp.Body)
+	if !json.Valid(resp.Body) {
+		encoded, err := json.Marshal(string(resp.Body))
+		if err != nil {
+			return nil, fmt.Errorf("encoding body as json string: %w", err)
+		}
+		body = encoded
+	}
+
+	envelope, err := json.Marshal(responseEnvelope{
+		Status:     resp.StatusCode,
+		Headers:    headers,
+		Body:       body,
+		DurationMS: resp.Duration.Milliseconds(),
+		URL:        resp.URL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling envelope: %w", err)
+	}
+	return envelope, nil
+}
@@ -0,0 +1,63 @@
+package pipeline
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+func Test_buildEnvelope(t *testing.T) {
+	tests := []struct {
+		name string
+		resp domain.CrawlResponse
+		want string
+	}{
+		{
+			name: "json_body",
+			resp: domain.CrawlResponse{
+				StatusCode: http.StatusOK,
+				Header: http.Header{
+					"X-Ratelimit-Remaining": {"10"},
+					"Vary":                  {"Accept", "Origin"},
+				},
+				Body:     []byte(`{"items":[1,2]}`),
+				URL:      "https://foo.com/api",
+				Duration: 1500 * time.Millisecond,
+			},
+			want: `{"status":200,"headers":{"vary":"Accept, Origin","x-ratelimit-remaining":"10"},` +
+				`"body":{"items":[1,2]},"duration_ms":1500,"url":"https://foo.com/api"}`,
+		},
+		{
+			name: "non_json_body",
+			resp: domain.CrawlResponse{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       []byte("<h1>Service Unavailable</h1>"),
+				URL:        "https://foo.com/api",
+				Duration:   20 * time.Millisecond,
+			},
+			want: `{"status":503,"headers":{},"body":"<h1>Service Unavailable</h1>",` +
+				`"duration_ms":20,"url":"https://foo.com/api"}`,
+		},
+		{
+			name: "empty_body",
+			resp: domain.CrawlResponse{
+				StatusCode: http.StatusNoContent,
+				URL:        "https://foo.com/api",
+			},
+			want: `{"status":204,"headers":{},"body":"","duration_ms":0,"url":"https://foo.com/api"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildEnvelope(tt.resp)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
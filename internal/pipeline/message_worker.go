@@ -4,37 +4,93 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"regexp"
+	"maps"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
 	"github.com/isutare412/crawlert/internal/core/domain"
 	"github.com/isutare412/crawlert/internal/core/port"
 	"github.com/isutare412/crawlert/internal/log"
+	"github.com/isutare412/crawlert/internal/template"
 )
 
-var regexPatternVariable = regexp.MustCompile(`\$\{?(\w+)\}?`)
+const variableSuppressedCount = "SUPPRESSED_COUNT"
+
+// digestCheckInterval is the interval to check whether quiet hours ended and
+// held messages should be sent as a digest.
+const digestCheckInterval = time.Minute
 
 type messageWorker struct {
-	template       string
-	messageSenders []port.MessageSender
-	queryOutputs   <-chan queryOutput
-	wg             sync.WaitGroup
+	template         string
+	resolveTemplate  string
+	errorTemplate    string
+	recoveryTemplate string
+	cooldown         time.Duration
+	jobName          string
+	receivers        []messageReceiver
+	outbox           port.Outbox
+	state            *crawlState
+	queryOutputs     <-chan queryOutput
+	wg               sync.WaitGroup
+}
+
+// messageReceiver is the message senders of a receiver, to which quiet hours of
+// the receiver apply.
+type messageReceiver struct {
+	name       string
+	quietHours *quietHours
+	senders    []outboundSender
 }
 
 func newMessageWorker(
-	message string,
-	messageSenders []port.MessageSender,
+	cfg CrawlConfig,
+	messageSenders []outboundSender,
+	outbox port.Outbox,
+	state *crawlState,
 	queryOutputs <-chan queryOutput,
-) *messageWorker {
-	return &messageWorker{
-		template:       message,
-		messageSenders: messageSenders,
-		queryOutputs:   queryOutputs,
-		wg:             sync.WaitGroup{},
+) (*messageWorker, error) {
+	var receivers []messageReceiver
+	indexes := make(map[string]int)
+	for _, s := range messageSenders {
+		if i, ok := indexes[s.receiver]; ok {
+			receivers[i].senders = append(receivers[i].senders, s)
+			continue
+		}
+
+		quietHoursCfg, ok := cfg.ReceiverQuietHours[s.receiver]
+		if !ok {
+			quietHoursCfg = cfg.QuietHours
+		}
+		quietHours, err := newQuietHours(quietHoursCfg)
+		if err != nil {
+			return nil, fmt.Errorf("creating quiet hours of receiver %s: %w", s.receiver, err)
+		}
+
+		indexes[s.receiver] = len(receivers)
+		receivers = append(receivers, messageReceiver{
+			name:       s.receiver,
+			quietHours: quietHours,
+			senders:    []outboundSender{s},
+		})
 	}
+
+	return &messageWorker{
+		template:         cfg.Message,
+		resolveTemplate:  cfg.ResolveMessage,
+		errorTemplate:    cfg.OnError.Message,
+		recoveryTemplate: cfg.OnError.RecoveryMessage,
+		cooldown:         cfg.Cooldown,
+		jobName:          cfg.Name,
+		receivers:        receivers,
+		outbox:           outbox,
+		state:            state,
+		queryOutputs:     queryOutputs,
+		wg:               sync.WaitGroup{},
+	}, nil
 }
 
 func (w *messageWorker) run() {
@@ -43,14 +99,37 @@ func (w *messageWorker) run() {
 		defer w.wg.Done()
 		defer log.RecoverIfPanic()
 
-		for output := range w.queryOutputs {
-			ctx := output.ctx
+		var digestTicks <-chan time.Time
+		if w.holdsDigest() {
+			ticker := time.NewTicker(digestCheckInterval)
+			defer ticker.Stop()
+			digestTicks = ticker.C
+		}
+
+		for {
+			select {
+			case output, ok := <-w.queryOutputs:
+				if !ok {
+					return
+				}
 
-			if err := w.sendMessage(ctx, output.queryResult); err != nil {
-				slog.ErrorContext(ctx, "failed to send message", "error", err)
-				continue
+				switch output.kind {
+				case alertKindMatched:
+					w.handleMatched(output.ctx, output.queryResult)
+				case alertKindResolved:
+					w.sendNotice(output.ctx, output.kind, w.resolveTemplate, output.queryResult.Variables,
+						"sent resolve message as query stopped matching")
+				case alertKindCrawlFailed:
+					w.sendNotice(output.ctx, output.kind, w.errorTemplate, output.queryResult.Variables,
+						"sent error message as crawl failed")
+				case alertKindCrawlRecovered:
+					w.sendNotice(output.ctx, output.kind, w.recoveryTemplate, output.queryResult.Variables,
+						"sent recovery message as crawl succeeded again")
+				}
+			case <-digestTicks:
+				ctx := log.WithValue(context.Background(), "jobName", w.jobName)
+				w.sendDigestIfQuietHoursEnded(ctx)
 			}
-			slog.InfoContext(ctx, "sent message as query matched")
 		}
 	}()
 }
@@ -59,20 +138,205 @@ func (w *messageWorker) shutdown() {
 	w.wg.Wait()
 }
 
+func (w *messageWorker) handleMatched(ctx context.Context, queryRes domain.QueryResult) {
+	suppressedCount, coolingDown, err := w.checkCooldown(ctx)
+	switch {
+	case err != nil:
+		slog.ErrorContext(ctx, "failed to check cooldown", "error", err)
+		return
+	case coolingDown:
+		slog.InfoContext(ctx, "suppressed message during cooldown", "suppressedCount", suppressedCount)
+		return
+	}
+
+	variables := maps.Clone(queryRes.Variables)
+	if variables == nil {
+		variables = make(map[string]string, 1)
+	}
+	variables[variableSuppressedCount] = strconv.Itoa(suppressedCount)
+
+	message := template.Render(w.template, variables)
+	if !w.deliver(ctx, message, variables) {
+		return
+	}
+	slog.InfoContext(ctx, "sent message as query matched")
+
+	err = w.state.update(ctx, func(state *domain.CrawlState) {
+		state.LastMessage = message
+		state.LastSentAt = time.Now()
+		state.SuppressedCount = 0
+		state.Firing = true
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record sent message", "error", err)
+	}
+}
+
+// sendNotice renders and sends a message about the state change of the crawl,
+// such as resolve, crawl failure and recovery. Notices are never suppressed by
+// cooldown. Crawl failures are recorded as alerted only if the error message is
+// sent, so that recovery is not sent for failures nobody was told about.
+func (w *messageWorker) sendNotice(
+	ctx context.Context,
+	kind alertKind,
+	tmpl string,
+	variables map[string]string,
+	logMsg string,
+) {
+	message := template.Render(tmpl, variables)
+	if !w.deliver(ctx, message, variables) {
+		return
+	}
+	slog.InfoContext(ctx, logMsg)
+
+	err := w.state.update(ctx, func(state *domain.CrawlState) {
+		state.LastMessage = message
+		if kind == alertKindCrawlFailed {
+			state.CrawlFailing = true
+		}
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record sent message", "error", err)
+	}
+}
+
+// deliver sends message to receivers not in quiet hours. Receivers in quiet
+// hours discard the message or hold it for a digest depending on the quiet
+// mode. It reports whether the message was sent to any receiver.
+func (w *messageWorker) deliver(ctx context.Context, message string, variables map[string]string) bool {
+	now := time.Now()
+
+	var senders []outboundSender
+	for _, r := range w.receivers {
+		if r.quietHours != nil && r.quietHours.isQuiet(now) {
+			w.holdDuringQuietHours(ctx, r, message)
+			continue
+		}
+		senders = append(senders, r.senders...)
+	}
+	if len(senders) == 0 {
+		return false
+	}
+
+	if err := w.sendMessage(ctx, senders, message, variables); err != nil {
+		slog.ErrorContext(ctx, "failed to send message", "error", err)
+		return false
+	}
+	return true
+}
+
+// holdDuringQuietHours discards message or keeps it for a digest of r,
+// depending on the quiet mode of r.
+func (w *messageWorker) holdDuringQuietHours(ctx context.Context, r messageReceiver, message string) {
+	switch r.quietHours.mode {
+	case QuietModeDigest:
+		err := w.state.update(ctx, func(state *domain.CrawlState) {
+			if state.PendingDigests == nil {
+				state.PendingDigests = make(map[string][]string)
+			}
+			state.PendingDigests[r.name] = append(state.PendingDigests[r.name], message)
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to hold message for digest", "receiver", r.name, "error", err)
+			return
+		}
+		slog.InfoContext(ctx, "held message for digest during quiet hours", "receiver", r.name)
+	default:
+		slog.InfoContext(ctx, "discarded message during quiet hours", "receiver", r.name)
+	}
+}
+
+// holdsDigest reports whether any receiver holds messages for a digest during
+// quiet hours.
+func (w *messageWorker) holdsDigest() bool {
+	for _, r := range w.receivers {
+		if r.quietHours != nil && r.quietHours.mode == QuietModeDigest {
+			return true
+		}
+	}
+	return false
+}
+
+// sendDigestIfQuietHoursEnded sends messages held during quiet hours as a
+// single digest message, for each receiver whose quiet hours ended.
+func (w *messageWorker) sendDigestIfQuietHoursEnded(ctx context.Context) {
+	now := time.Now()
+	for _, r := range w.receivers {
+		if r.quietHours == nil || r.quietHours.mode != QuietModeDigest || r.quietHours.isQuiet(now) {
+			continue
+		}
+		w.sendDigest(ctx, r)
+	}
+}
+
+func (w *messageWorker) sendDigest(ctx context.Context, r messageReceiver) {
+	state, err := w.state.get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get crawl state", "error", err)
+		return
+	}
+	pending := state.PendingDigests[r.name]
+	if len(pending) == 0 {
+		return
+	}
+
+	message := buildDigest(pending)
+	if err := w.sendMessage(ctx, r.senders, message, nil); err != nil {
+		slog.ErrorContext(ctx, "failed to send digest message", "receiver", r.name, "error", err)
+		return
+	}
+	slog.InfoContext(ctx, "sent digest message as quiet hours ended",
+		"receiver", r.name, "heldMessages", len(pending))
+
+	sentCount := len(pending)
+	err = w.state.update(ctx, func(state *domain.CrawlState) {
+		state.PendingDigests[r.name] = state.PendingDigests[r.name][sentCount:]
+		if len(state.PendingDigests[r.name]) == 0 {
+			delete(state.PendingDigests, r.name)
+		}
+		state.LastMessage = message
+		state.LastSentAt = time.Now()
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record sent digest message", "receiver", r.name, "error", err)
+	}
+}
+
+// checkCooldown reports whether the message should be suppressed because the
+// previous message was sent within the cooldown. The suppressed count is
+// increased if so. It returns the number of messages suppressed since the
+// previous message.
+func (w *messageWorker) checkCooldown(ctx context.Context) (suppressedCount int, coolingDown bool, err error) {
+	err = w.state.update(ctx, func(state *domain.CrawlState) {
+		if w.cooldown > 0 && time.Since(state.LastSentAt) < w.cooldown {
+			state.SuppressedCount++
+			coolingDown = true
+		}
+		suppressedCount = state.SuppressedCount
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("updating crawl state: %w", err)
+	}
+
+	return suppressedCount, coolingDown, nil
+}
+
 func (w *messageWorker) sendMessage(
 	ctx context.Context,
-	queryRes domain.QueryResult,
+	senders []outboundSender,
+	message string,
+	variables map[string]string,
 ) error {
-	message := buildMessage(w.template, queryRes.Variables)
+	msg := domain.Message{
+		CrawlName: w.jobName,
+		Text:      message,
+		Variables: variables,
+	}
 
 	eg := errgroup.Group{}
-	for _, sender := range w.messageSenders {
+	for _, s := range senders {
 		eg.Go(func() error {
-			if err := sender.SendMessage(ctx, message); err != nil {
-				return fmt.Errorf("sending message: %w", err)
-			}
-
-			return nil
+			return w.sendOrPushToOutbox(ctx, s, msg)
 		})
 	}
 
@@ -83,13 +347,30 @@ func (w *messageWorker) sendMessage(
 	return nil
 }
 
-func buildMessage(template string, variables map[string]string) string {
-	return regexPatternVariable.ReplaceAllStringFunc(template, func(match string) string {
-		key := strings.Trim(match, "${}")
+// sendOrPushToOutbox sends msg with s. The message is pushed to outbox instead
+// if delivery fails, or if former messages of s are still in outbox so that
+// messages are delivered in order.
+func (w *messageWorker) sendOrPushToOutbox(ctx context.Context, s outboundSender, msg domain.Message) error {
+	_, pending, err := w.outbox.PeekOutboxMessage(ctx, s.key)
+	if err != nil {
+		return fmt.Errorf("peeking outbox message: %w", err)
+	}
 
-		if value, ok := variables[key]; ok {
-			return value
+	if !pending {
+		err := s.sender.SendMessage(ctx, msg)
+		if err == nil {
+			return nil
 		}
-		return match
-	})
+		slog.WarnContext(ctx, "failed to send message; pushing to outbox", "sender", s.key, "error", err)
+	}
+
+	if err := pushToOutbox(ctx, w.outbox, s.key, msg); err != nil {
+		return fmt.Errorf("keeping undelivered message of %s: %w", s.key, err)
+	}
+	return nil
+}
+
+func buildDigest(messages []string) string {
+	header := fmt.Sprintf("Digest of %d messages held during quiet hours", len(messages))
+	return header + "\n\n" + strings.Join(messages, "\n\n---\n\n")
 }
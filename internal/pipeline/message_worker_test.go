@@ -1,48 +1,139 @@
 package pipeline
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/core/port/mockport"
+	"github.com/isutare412/crawlert/internal/memory"
 )
 
-func Test_buildMessage(t *testing.T) {
-	type args struct {
-		template  string
-		variables map[string]string
-	}
+func Test_messageWorker_checkCooldown(t *testing.T) {
 	tests := []struct {
-		name string
-		args args
-		want string
+		name              string
+		cooldown          time.Duration
+		state             domain.CrawlState
+		wantCoolingDown   bool
+		wantSuppressedCnt int
 	}{
 		{
-			name: "multiple_variables",
-			args: args{
-				template: `hello $ONE, bye ${TWO}`,
-				variables: map[string]string{
-					"ONE": "tester1",
-					"TWO": "tester2",
-				},
+			name:     "no_cooldown",
+			cooldown: 0,
+			state: domain.CrawlState{
+				LastSentAt: time.Now(),
 			},
-			want: `hello tester1, bye tester2`,
+			wantCoolingDown:   false,
+			wantSuppressedCnt: 0,
 		},
 		{
-			name: "unknown_variable",
-			args: args{
-				template: `hello $UNKNOWN, bye ${SUSPECT}`,
-				variables: map[string]string{
-					"ONE": "tester1",
-				},
+			name:     "within_cooldown",
+			cooldown: time.Hour,
+			state: domain.CrawlState{
+				LastSentAt:      time.Now().Add(-time.Minute),
+				SuppressedCount: 2,
 			},
-			want: `hello $UNKNOWN, bye ${SUSPECT}`,
+			wantCoolingDown:   true,
+			wantSuppressedCnt: 3,
+		},
+		{
+			name:     "cooldown_expired",
+			cooldown: time.Hour,
+			state: domain.CrawlState{
+				LastSentAt:      time.Now().Add(-2 * time.Hour),
+				SuppressedCount: 2,
+			},
+			wantCoolingDown:   false,
+			wantSuppressedCnt: 2,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := buildMessage(tt.args.template, tt.args.variables)
-			assert.Equal(t, tt.want, got)
+			ctx := context.Background()
+			store := memory.NewStore()
+			require.NoError(t, store.PutCrawlState(ctx, "test", tt.state))
+
+			w, err := newMessageWorker(
+				CrawlConfig{Name: "test", Cooldown: tt.cooldown}, nil, store, newCrawlState("test", store), nil)
+			require.NoError(t, err)
+
+			gotSuppressedCnt, gotCoolingDown, err := w.checkCooldown(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCoolingDown, gotCoolingDown)
+			assert.Equal(t, tt.wantSuppressedCnt, gotSuppressedCnt)
 		})
 	}
 }
+
+func Test_messageWorker_deliver(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+
+	pricing := mockport.NewMockMessageSender(t)
+	pricing.EXPECT().SendMessage(mock.Anything, mock.Anything).Return(nil).Once()
+	ops := mockport.NewMockMessageSender(t)
+
+	allDay := []QuietWindowConfig{{Start: "00:00", End: "00:00"}}
+	cfg := CrawlConfig{
+		Name:      "test",
+		Receivers: []string{"pricing", "ops"},
+		ReceiverQuietHours: map[string]QuietHoursConfig{
+			"ops": {Mode: QuietModeDigest, Windows: allDay},
+		},
+	}
+	senders := []outboundSender{
+		{key: "pricing/0", receiver: "pricing", sender: pricing},
+		{key: "ops/0", receiver: "ops", sender: ops},
+	}
+
+	w, err := newMessageWorker(cfg, senders, store, newCrawlState("test", store), nil)
+	require.NoError(t, err)
+
+	// Message is sent to pricing, and held for a digest of ops in quiet hours.
+	assert.True(t, w.deliver(ctx, "price dropped", nil))
+
+	state, err := store.GetCrawlState(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"ops": {"price dropped"}}, state.PendingDigests)
+
+	// Message is not sent if every receiver is in quiet hours.
+	cfg.QuietHours = QuietHoursConfig{Mode: QuietModeDiscard, Windows: allDay}
+	w, err = newMessageWorker(cfg, senders, store, newCrawlState("test", store), nil)
+	require.NoError(t, err)
+	assert.False(t, w.deliver(ctx, "price dropped again", nil))
+}
+
+func Test_messageWorker_sendNotice_crawlFailed(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+
+	sender := mockport.NewMockMessageSender(t)
+	sender.EXPECT().SendMessage(mock.Anything, mock.Anything).Return(nil).Once()
+	senders := []outboundSender{{key: "default/0", receiver: "default", sender: sender}}
+
+	allDay := []QuietWindowConfig{{Start: "00:00", End: "00:00"}}
+	quiet, err := newMessageWorker(
+		CrawlConfig{Name: "test", QuietHours: QuietHoursConfig{Mode: QuietModeDiscard, Windows: allDay}},
+		senders, store, newCrawlState("test", store), nil)
+	require.NoError(t, err)
+
+	// Failure discarded during quiet hours is not recorded as alerted.
+	quiet.sendNotice(ctx, alertKindCrawlFailed, "failed", nil, "sent error message")
+	state, err := store.GetCrawlState(ctx, "test")
+	require.NoError(t, err)
+	assert.False(t, state.CrawlFailing)
+
+	w, err := newMessageWorker(CrawlConfig{Name: "test"}, senders, store, newCrawlState("test", store), nil)
+	require.NoError(t, err)
+
+	w.sendNotice(ctx, alertKindCrawlFailed, "failed", nil, "sent error message")
+	state, err = store.GetCrawlState(ctx, "test")
+	require.NoError(t, err)
+	assert.True(t, state.CrawlFailing)
+}
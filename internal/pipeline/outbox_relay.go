@@ -0,0 +1,182 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/core/port"
+	"github.com/isutare412/crawlert/internal/log"
+)
+
+// outboundSender is a message sender identified by key, under which its
+// undelivered messages are kept in outbox. Key is "<receiver>/<hash>", where
+// hash is derived from the destination of the sender.
+type outboundSender struct {
+	key      string
+	receiver string
+	sender   port.MessageSender
+}
+
+// outboxRelay delivers messages in outbox in the background.
+type outboxRelay struct {
+	outbox   port.Outbox
+	senders  map[string]port.MessageSender
+	interval time.Duration
+	maxAge   time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newOutboxRelay(cfg OutboxConfig, outbox port.Outbox, senders []outboundSender) *outboxRelay {
+	sendersByKey := make(map[string]port.MessageSender, len(senders))
+	for _, s := range senders {
+		sendersByKey[s.key] = s.sender
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &outboxRelay{
+		outbox:   outbox,
+		senders:  sendersByKey,
+		interval: cfg.RelayInterval,
+		maxAge:   cfg.MaxAge,
+		ctx:      ctx,
+		cancel:   cancel,
+		wg:       sync.WaitGroup{},
+	}
+}
+
+func (r *outboxRelay) run() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer log.RecoverIfPanic()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			r.relayAll(r.ctx)
+
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (r *outboxRelay) shutdown() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// countPending returns the total number of messages in outbox.
+func (r *outboxRelay) countPending(ctx context.Context) (int, error) {
+	counts, err := r.outbox.CountOutboxMessages(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("counting outbox messages: %w", err)
+	}
+
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total, nil
+}
+
+func (r *outboxRelay) relayAll(ctx context.Context) {
+	counts, err := r.outbox.CountOutboxMessages(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to count outbox messages", "error", err)
+		return
+	}
+
+	for key := range counts {
+		sender, ok := r.senders[key]
+		if !ok {
+			if err := r.drop(ctx, key); err != nil {
+				slog.WarnContext(ctx, "failed to drop outbox messages", "sender", key, "error", err)
+			}
+			continue
+		}
+
+		if err := r.relay(ctx, key, sender); err != nil {
+			slog.WarnContext(ctx, "failed to relay outbox messages", "sender", key, "error", err)
+		}
+	}
+
+	pending, err := r.countPending(ctx)
+	switch {
+	case err != nil:
+		slog.ErrorContext(ctx, "failed to count pending outbox messages", "error", err)
+	case pending > 0:
+		slog.WarnContext(ctx, "messages are pending in outbox", "pending", pending)
+	}
+}
+
+// relay delivers messages of the sender in order until outbox of the sender is
+// empty or delivery fails. Messages older than max age are dropped.
+func (r *outboxRelay) relay(ctx context.Context, key string, sender port.MessageSender) error {
+	for {
+		message, ok, err := r.outbox.PeekOutboxMessage(ctx, key)
+		switch {
+		case err != nil:
+			return fmt.Errorf("peeking outbox message: %w", err)
+		case !ok:
+			return nil
+		}
+
+		if time.Since(message.CreatedAt) > r.maxAge {
+			slog.WarnContext(ctx, "dropped expired outbox message", "sender", key, "crawlName", message.Message.CrawlName,
+				"createdAt", message.CreatedAt)
+		} else {
+			if err := sender.SendMessage(ctx, message.Message); err != nil {
+				return fmt.Errorf("sending message: %w", err)
+			}
+			slog.InfoContext(ctx, "delivered outbox message", "sender", key, "crawlName", message.Message.CrawlName)
+		}
+
+		if err := r.outbox.DeleteOutboxMessage(ctx, key, message.ID); err != nil {
+			return fmt.Errorf("deleting outbox message: %w", err)
+		}
+	}
+}
+
+// drop deletes messages of a sender which is no longer configured, as they
+// cannot be delivered.
+func (r *outboxRelay) drop(ctx context.Context, key string) error {
+	for {
+		message, ok, err := r.outbox.PeekOutboxMessage(ctx, key)
+		switch {
+		case err != nil:
+			return fmt.Errorf("peeking outbox message: %w", err)
+		case !ok:
+			return nil
+		}
+
+		if err := r.outbox.DeleteOutboxMessage(ctx, key, message.ID); err != nil {
+			return fmt.Errorf("deleting outbox message: %w", err)
+		}
+		slog.WarnContext(ctx, "dropped outbox message of unknown sender", "sender", key,
+			"crawlName", message.Message.CrawlName, "createdAt", message.CreatedAt)
+	}
+}
+
+// pushToOutbox keeps message in outbox to be delivered later.
+func pushToOutbox(ctx context.Context, outbox port.Outbox, key string, message domain.Message) error {
+	err := outbox.PushOutboxMessage(ctx, key, domain.OutboxMessage{
+		Message:   message,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("pushing outbox message: %w", err)
+	}
+	return nil
+}
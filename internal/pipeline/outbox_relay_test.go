@@ -0,0 +1,85 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/core/port/mockport"
+	"github.com/isutare412/crawlert/internal/memory"
+)
+
+func Test_messageWorker_sendOrPushToOutbox(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+
+	sender := mockport.NewMockMessageSender(t)
+	sender.EXPECT().SendMessage(mock.Anything, mock.Anything).Return(errors.New("unreachable")).Once()
+
+	w, err := newMessageWorker(CrawlConfig{Name: "test"}, nil, store, newCrawlState("test", store), nil)
+	require.NoError(t, err)
+
+	s := outboundSender{key: "default/0", sender: sender}
+
+	// Failed message is pushed to outbox.
+	require.NoError(t, w.sendOrPushToOutbox(ctx, s, domain.Message{CrawlName: "test", Text: "first"}))
+
+	// Later message is pushed to outbox without sending, to keep the order.
+	require.NoError(t, w.sendOrPushToOutbox(ctx, s, domain.Message{CrawlName: "test", Text: "second"}))
+
+	counts, err := store.CountOutboxMessages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"default/0": 2}, counts)
+}
+
+func Test_outboxRelay_relayAll(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+
+	push := func(key, text string, createdAt time.Time) {
+		err := store.PushOutboxMessage(ctx, key, domain.OutboxMessage{
+			Message:   domain.Message{CrawlName: "test", Text: text},
+			CreatedAt: createdAt,
+		})
+		require.NoError(t, err)
+	}
+	push("default/0", "expired", time.Now().Add(-2*time.Hour))
+	push("default/0", "first", time.Now())
+	push("default/0", "second", time.Now())
+	push("ops/0", "third", time.Now())
+	push("removed/0", "fourth", time.Now())
+
+	var delivered []string
+	healthy := mockport.NewMockMessageSender(t)
+	healthy.EXPECT().
+		SendMessage(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, message domain.Message) {
+			delivered = append(delivered, message.Text)
+		}).
+		Return(nil)
+
+	unreachable := mockport.NewMockMessageSender(t)
+	unreachable.EXPECT().SendMessage(mock.Anything, mock.Anything).Return(errors.New("unreachable")).Once()
+
+	relay := newOutboxRelay(OutboxConfig{RelayInterval: time.Minute, MaxAge: time.Hour}, store, []outboundSender{
+		{key: "default/0", sender: healthy},
+		{key: "ops/0", sender: unreachable},
+	})
+	relay.relayAll(ctx)
+
+	assert.Equal(t, []string{"first", "second"}, delivered)
+
+	counts, err := store.CountOutboxMessages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"ops/0": 1}, counts)
+
+	pending, err := relay.countPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, pending)
+}
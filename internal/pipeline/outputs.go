@@ -14,10 +14,30 @@ type triggerOutput struct {
 type crawlOutput struct {
 	ctx           context.Context
 	crawlResponse domain.CrawlResponse
+
+	// err is the error of the crawl. crawlResponse is empty if err is not nil.
+	err error
 }
 
+// alertKind is the reason why a message is sent.
+type alertKind int
+
+const (
+	// alertKindMatched means the check query matched.
+	alertKindMatched alertKind = iota
+	// alertKindResolved means the check query stopped matching.
+	alertKindResolved
+	// alertKindCrawlFailed means the crawl failed consecutively as many as
+	// the threshold.
+	alertKindCrawlFailed
+	// alertKindCrawlRecovered means the crawl succeeded after failures were
+	// alerted.
+	alertKindCrawlRecovered
+)
+
 type queryOutput struct {
 	ctx           context.Context
+	kind          alertKind
 	crawlResponse domain.CrawlResponse
 	queryResult   domain.QueryResult
 }
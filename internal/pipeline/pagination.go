@@ -0,0 +1,290 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/query"
+)
+
+// paginator crawls every page of a target, merging items of the pages into a
+// single JSON array.
+type paginator struct {
+	strategy   PaginationStrategy
+	param      string
+	start      int
+	limitParam string
+	limit      int
+	cursor     *query.Expression
+	items      *query.Expression
+	maxPages   int
+}
+
+// newPaginator returns nil if pagination is not configured.
+func newPaginator(cfg PaginationConfig) (*paginator, error) {
+	if cfg.Strategy == "" {
+		return nil, nil
+	}
+
+	items, err := query.NewExpression(cfg.Items)
+	if err != nil {
+		return nil, fmt.Errorf("creating items query: %w", err)
+	}
+
+	var cursor *query.Expression
+	if cfg.Strategy == PaginationCursor {
+		cursor, err = query.NewExpression(cfg.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("creating cursor query: %w", err)
+		}
+	}
+
+	return &paginator{
+		strategy:   cfg.Strategy,
+		param:      cfg.Param,
+		start:      cfg.Start,
+		limitParam: cfg.LimitParam,
+		limit:      cfg.Limit,
+		cursor:     cursor,
+		items:      items,
+		maxPages:   cfg.MaxPages,
+	}, nil
+}
+
+type crawlPageFunc func(ctx context.Context, req domain.CrawlRequest) (domain.CrawlResponse, error)
+
+// crawl crawls pages starting from req with crawlPage, until no more pages
+// remain or max pages are crawled. The returned response is the one of the
+// last page, of which body is replaced with the merged items.
+func (p *paginator) crawl(ctx context.Context, req domain.CrawlRequest, crawlPage crawlPageFunc) (
+	domain.CrawlResponse, error,
+) {
+	var (
+		position = p.start
+		pageReq  = req
+		items    = make([]any, 0)
+		resp     domain.CrawlResponse
+		duration time.Duration
+		err      error
+	)
+	if p.strategy == PaginationPage || p.strategy == PaginationOffset {
+		pageReq, err = p.pageRequest(req, strconv.Itoa(position))
+		if err != nil {
+			return domain.CrawlResponse{}, fmt.Errorf("building request of page 1: %w", err)
+		}
+	}
+
+	for page := 1; ; page++ {
+		resp, err = crawlPage(ctx, pageReq)
+		if err != nil {
+			return domain.CrawlResponse{}, fmt.Errorf("crawling page %d: %w", page, err)
+		}
+		duration += resp.Duration
+
+		// Numbers are decoded as they are, so that large cursors and ids are
+		// not rounded.
+		var doc any
+		if err := decodeJSON(resp.Body, &doc); err != nil {
+			return domain.CrawlResponse{}, fmt.Errorf("unmarshaling page %d: %w", page, err)
+		}
+
+		pageItems, err := p.pageItems(doc)
+		if err != nil {
+			return domain.CrawlResponse{}, fmt.Errorf("collecting items of page %d: %w", page, err)
+		}
+		items = append(items, pageItems...)
+
+		var next string
+		switch p.strategy {
+		case PaginationPage:
+			position++
+			next = strconv.Itoa(position)
+		case PaginationOffset:
+			if p.limit > 0 {
+				position += p.limit
+			} else {
+				position += len(pageItems)
+			}
+			next = strconv.Itoa(position)
+		case PaginationCursor:
+			next, err = p.nextCursor(doc)
+			if err != nil {
+				return domain.CrawlResponse{}, fmt.Errorf("finding cursor of page %d: %w", page, err)
+			}
+		case PaginationLink:
+			next = nextLink(resp.Header, resp.URL)
+
+			// Requests carry credentials of the target, which must not be
+			// sent to other origins.
+			if next != "" && !sameOrigin(next, req.URL) {
+				return domain.CrawlResponse{}, fmt.Errorf("next link %s of page %d is not on the origin of target",
+					next, page)
+			}
+		}
+
+		if next == "" || p.isLastPage(len(pageItems)) {
+			break
+		}
+		if page >= p.maxPages {
+			slog.WarnContext(ctx, "stopped pagination at max pages", "maxPages", p.maxPages)
+			break
+		}
+
+		if p.strategy == PaginationLink {
+			pageReq.URL = next
+		} else {
+			pageReq, err = p.pageRequest(req, next)
+			if err != nil {
+				return domain.CrawlResponse{}, fmt.Errorf("building request of page %d: %w", page+1, err)
+			}
+		}
+	}
+
+	body, err := json.Marshal(items)
+	if err != nil {
+		return domain.CrawlResponse{}, fmt.Errorf("marshaling merged items: %w", err)
+	}
+	resp.Body = body
+	resp.Duration = duration
+
+	return resp, nil
+}
+
+// pageRequest returns req with query parameters of the page at position,
+// which is a page number, an offset or a cursor.
+func (p *paginator) pageRequest(req domain.CrawlRequest, position string) (domain.CrawlRequest, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return domain.CrawlRequest{}, fmt.Errorf("parsing url: %w", err)
+	}
+
+	values := u.Query()
+	values.Set(p.param, position)
+	if p.strategy == PaginationOffset && p.limit > 0 {
+		values.Set(p.limitParam, strconv.Itoa(p.limit))
+	}
+	u.RawQuery = values.Encode()
+
+	req.URL = u.String()
+	return req, nil
+}
+
+func (p *paginator) pageItems(doc any) ([]any, error) {
+	result, ok, err := p.items.EvaluateValue(doc)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("applying items query: %w", err)
+	case !ok || result == nil:
+		return nil, nil
+	}
+
+	items, ok := result.([]any)
+	if !ok {
+		return nil, fmt.Errorf("items query should produce an array, but got %T", result)
+	}
+	return items, nil
+}
+
+// nextCursor returns the cursor of the next page, or empty string if doc is
+// the last page.
+func (p *paginator) nextCursor(doc any) (string, error) {
+	result, ok, err := p.cursor.EvaluateValue(doc)
+	switch {
+	case err != nil:
+		return "", fmt.Errorf("applying cursor query: %w", err)
+	case !ok || result == nil || result == false:
+		return "", nil
+	}
+
+	if s, ok := result.(string); ok {
+		return s, nil
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("marshaling cursor: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// isLastPage reports whether a page with itemCount items is the last page.
+// Pages are exhausted once a page is empty, or once an offset page has fewer
+// items than its limit.
+func (p *paginator) isLastPage(itemCount int) bool {
+	switch p.strategy {
+	case PaginationPage:
+		return itemCount == 0
+	case PaginationOffset:
+		return itemCount == 0 || (p.limit > 0 && itemCount < p.limit)
+	default:
+		return false
+	}
+}
+
+// nextLink returns the URL of rel="next" in Link headers, resolved against
+// base. Empty string is returned if not found.
+func nextLink(header http.Header, base string) string {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			target, params, found := strings.Cut(link, ";")
+			if !found {
+				continue
+			}
+
+			target = strings.TrimSpace(target)
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			if !hasNextRel(params) {
+				continue
+			}
+
+			ref, err := url.Parse(target[1 : len(target)-1])
+			if err != nil {
+				continue
+			}
+			baseURL, err := url.Parse(base)
+			if err != nil {
+				return ref.String()
+			}
+			return baseURL.ResolveReference(ref).String()
+		}
+	}
+	return ""
+}
+
+// sameOrigin reports whether URLs a and b have the same scheme and host.
+func sameOrigin(a, b string) bool {
+	urlA, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	urlB, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(urlA.Scheme, urlB.Scheme) && strings.EqualFold(urlA.Host, urlB.Host)
+}
+
+func hasNextRel(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if !strings.EqualFold(key, "rel") {
+			continue
+		}
+		for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+			if strings.EqualFold(rel, "next") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+func Test_paginator_crawl(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       PaginationConfig
+		pages     map[string]domain.CrawlResponse
+		wantURLs  []string
+		wantItems string
+	}{
+		{
+			name: "page_until_empty",
+			cfg:  PaginationConfig{Strategy: PaginationPage, Param: "page", Start: 1, Items: ".data", MaxPages: 10},
+			pages: map[string]domain.CrawlResponse{
+				"https://foo.com/api?page=1&q=x": {Body: []byte(`{"data":[1,2]}`)},
+				"https://foo.com/api?page=2&q=x": {Body: []byte(`{"data":[3]}`)},
+				"https://foo.com/api?page=3&q=x": {Body: []byte(`{"data":[]}`)},
+			},
+			wantURLs:  []string{"https://foo.com/api?page=1&q=x", "https://foo.com/api?page=2&q=x", "https://foo.com/api?page=3&q=x"},
+			wantItems: `[1,2,3]`,
+		},
+		{
+			name: "offset_until_short_page",
+			cfg: PaginationConfig{
+				Strategy: PaginationOffset, Param: "offset", LimitParam: "limit", Limit: 2, Items: ".", MaxPages: 10,
+			},
+			pages: map[string]domain.CrawlResponse{
+				"https://foo.com/api?limit=2&offset=0&q=x": {Body: []byte(`[1,2]`)},
+				"https://foo.com/api?limit=2&offset=2&q=x": {Body: []byte(`[3]`)},
+			},
+			wantURLs:  []string{"https://foo.com/api?limit=2&offset=0&q=x", "https://foo.com/api?limit=2&offset=2&q=x"},
+			wantItems: `[1,2,3]`,
+		},
+		{
+			name: "cursor_until_null",
+			cfg: PaginationConfig{
+				Strategy: PaginationCursor, Param: "after", Cursor: ".next", Items: ".items", MaxPages: 10,
+			},
+			pages: map[string]domain.CrawlResponse{
+				"https://foo.com/api?q=x":          {Body: []byte(`{"items":["a"],"next":"c1"}`)},
+				"https://foo.com/api?after=c1&q=x": {Body: []byte(`{"items":["b"],"next":null}`)},
+			},
+			wantURLs:  []string{"https://foo.com/api?q=x", "https://foo.com/api?after=c1&q=x"},
+			wantItems: `["a","b"]`,
+		},
+		{
+			name: "cursor_large_number",
+			cfg: PaginationConfig{
+				Strategy: PaginationCursor, Param: "after", Cursor: ".next", Items: ".items", MaxPages: 10,
+			},
+			pages: map[string]domain.CrawlResponse{
+				"https://foo.com/api?q=x":                           {Body: []byte(`{"items":[{"id":9007199254740993}],"next":1234567890123456789}`)},
+				"https://foo.com/api?after=1234567890123456789&q=x": {Body: []byte(`{"items":[],"next":null}`)},
+			},
+			wantURLs:  []string{"https://foo.com/api?q=x", "https://foo.com/api?after=1234567890123456789&q=x"},
+			wantItems: `[{"id":9007199254740993}]`,
+		},
+		{
+			name: "link_header",
+			cfg:  PaginationConfig{Strategy: PaginationLink, Items: ".", MaxPages: 10},
+			pages: map[string]domain.CrawlResponse{
+				"https://foo.com/api?q=x": {
+					Header: http.Header{"Link": {`<https://foo.com/api?q=x&page=1>; rel="prev", </api?q=x&page=2>; rel="next"`}},
+					Body:   []byte(`[1]`),
+				},
+				"https://foo.com/api?q=x&page=2": {Body: []byte(`[2]`)},
+			},
+			wantURLs:  []string{"https://foo.com/api?q=x", "https://foo.com/api?q=x&page=2"},
+			wantItems: `[1,2]`,
+		},
+		{
+			name: "stop_at_max_pages",
+			cfg:  PaginationConfig{Strategy: PaginationPage, Param: "page", Start: 0, Items: ".", MaxPages: 2},
+			pages: map[string]domain.CrawlResponse{
+				"https://foo.com/api?page=0&q=x": {Body: []byte(`[1]`)},
+				"https://foo.com/api?page=1&q=x": {Body: []byte(`[2]`)},
+			},
+			wantURLs:  []string{"https://foo.com/api?page=0&q=x", "https://foo.com/api?page=1&q=x"},
+			wantItems: `[1,2]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newPaginator(tt.cfg)
+			require.NoError(t, err)
+
+			var gotURLs []string
+			crawlPage := func(_ context.Context, req domain.CrawlRequest) (domain.CrawlResponse, error) {
+				gotURLs = append(gotURLs, req.URL)
+				resp, ok := tt.pages[req.URL]
+				if !ok {
+					return domain.CrawlResponse{}, fmt.Errorf("unexpected url %s", req.URL)
+				}
+				resp.URL = req.URL
+				return resp, nil
+			}
+
+			resp, err := p.crawl(context.Background(), domain.CrawlRequest{URL: "https://foo.com/api?q=x"}, crawlPage)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantURLs, gotURLs)
+			assert.Equal(t, tt.wantItems, string(resp.Body))
+		})
+	}
+}
+
+func Test_paginator_crawl_itemsNotArray(t *testing.T) {
+	p, err := newPaginator(PaginationConfig{Strategy: PaginationPage, Param: "page", Items: ".", MaxPages: 10})
+	require.NoError(t, err)
+
+	_, err = p.crawl(context.Background(), domain.CrawlRequest{URL: "https://foo.com/api"},
+		func(context.Context, domain.CrawlRequest) (domain.CrawlResponse, error) {
+			return domain.CrawlResponse{Body: []byte(`{"data":[]}`)}, nil
+		})
+	assert.ErrorContains(t, err, "items query should produce an array")
+}
+
+func Test_paginator_crawl_crossOriginLink(t *testing.T) {
+	p, err := newPaginator(PaginationConfig{Strategy: PaginationLink, Items: ".", MaxPages: 10})
+	require.NoError(t, err)
+
+	var gotURLs []string
+	_, err = p.crawl(context.Background(), domain.CrawlRequest{URL: "https://foo.com/api"},
+		func(_ context.Context, req domain.CrawlRequest) (domain.CrawlResponse, error) {
+			gotURLs = append(gotURLs, req.URL)
+			return domain.CrawlResponse{
+				URL:    req.URL,
+				Header: http.Header{"Link": {`<https://evil.com/api?page=2>; rel="next"`}},
+				Body:   []byte(`[1]`),
+			}, nil
+		})
+	assert.ErrorContains(t, err, "not on the origin of target")
+	assert.Equal(t, []string{"https://foo.com/api"}, gotURLs)
+}
@@ -1,39 +1,69 @@
 package pipeline
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 
+	"github.com/isutare412/crawlert/internal/auth"
 	"github.com/isutare412/crawlert/internal/core/port"
 )
 
 type Processor struct {
 	workerGroups []*workerGroup
+	outboxRelay  *outboxRelay
 }
 
 func NewProcessor(
 	cfg ProcessorConfig,
 	httpCrawler port.HTTPCrawler,
-	messageSenders []port.MessageSender,
+	receivers map[string][]port.MessageSender,
+	stateStore port.StateStore,
+	outbox port.Outbox,
 ) (*Processor, error) {
 	cfgsEnabled := filterEnabledConfig(cfg.Crawls)
 	if len(cfgsEnabled) == 0 {
 		return nil, fmt.Errorf("all crawls are disabled")
 	}
 
+	outboundSenders := buildOutboundSenders(receivers)
+
+	// Authenticators are created by a single provider, so that OAuth2 tokens
+	// are shared by crawls with the same credentials.
+	authProvider := auth.NewProvider()
+
 	workerGroups := make([]*workerGroup, 0, len(cfgsEnabled))
 	for _, cfg := range cfgsEnabled {
-		group, err := newWorkerGroup(cfg, httpCrawler, messageSenders)
+		messageSenders, err := routeMessageSenders(cfg.Receivers, outboundSenders)
+		if err != nil {
+			return nil, fmt.Errorf("routing receivers of %s: %w", cfg.Name, err)
+		}
+
+		group, err := newWorkerGroup(cfg, httpCrawler, authProvider, messageSenders, stateStore, outbox)
 		if err != nil {
 			return nil, fmt.Errorf("creating worker group of %s: %w", cfg.Name, err)
 		}
 
-		slog.Info("worker group created", "jobName", cfg.Name, "interval", cfg.Interval.String())
+		if cfg.Schedule != "" {
+			slog.Info("worker group created", "jobName", cfg.Name, "schedule", cfg.Schedule, "timezone", cfg.Timezone,
+				"receivers", cfg.Receivers)
+		} else {
+			slog.Info("worker group created", "jobName", cfg.Name, "interval", cfg.Interval.String(),
+				"receivers", cfg.Receivers)
+		}
 		workerGroups = append(workerGroups, group)
 	}
 
+	var allSenders []outboundSender
+	for _, senders := range outboundSenders {
+		allSenders = append(allSenders, senders...)
+	}
+
 	return &Processor{
 		workerGroups: workerGroups,
+		outboxRelay:  newOutboxRelay(cfg.Outbox, outbox, allSenders),
 	}, nil
 }
 
@@ -41,12 +71,20 @@ func (p *Processor) Run() {
 	for _, group := range p.workerGroups {
 		group.run()
 	}
+	p.outboxRelay.run()
 }
 
 func (p *Processor) Shutdown() {
 	for _, group := range p.workerGroups {
 		group.shutdown()
 	}
+	p.outboxRelay.shutdown()
+}
+
+// CountPendingMessages returns the number of undelivered messages waiting in
+// outbox.
+func (p *Processor) CountPendingMessages(ctx context.Context) (int, error) {
+	return p.outboxRelay.countPending(ctx)
 }
 
 func filterEnabledConfig(cfgs []CrawlConfig) []CrawlConfig {
@@ -58,3 +96,44 @@ func filterEnabledConfig(cfgs []CrawlConfig) []CrawlConfig {
 	}
 	return enabled
 }
+
+func buildOutboundSenders(receivers map[string][]port.MessageSender) map[string][]outboundSender {
+	outboundSenders := make(map[string][]outboundSender, len(receivers))
+	for name, senders := range receivers {
+		for _, sender := range senders {
+			outboundSenders[name] = append(outboundSenders[name], outboundSender{
+				key:      outboxKey(name, sender.Destination()),
+				receiver: name,
+				sender:   sender,
+			})
+		}
+	}
+	return outboundSenders
+}
+
+// outboxKey returns the key of messages of a sender in outbox. The destination
+// is hashed so that secrets in it, such as webhook tokens, are not stored.
+func outboxKey(receiver, destination string) string {
+	hash := sha256.Sum256([]byte(destination))
+	return receiver + "/" + hex.EncodeToString(hash[:8])
+}
+
+// routeMessageSenders returns message senders of receivers of names. Receivers
+// named more than once are used once.
+func routeMessageSenders(names []string, receivers map[string][]outboundSender) ([]outboundSender, error) {
+	var senders []outboundSender
+	routed := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if _, ok := routed[name]; ok {
+			continue
+		}
+		routed[name] = struct{}{}
+
+		receiverSenders, ok := receivers[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown receiver %s", name)
+		}
+		senders = append(senders, receiverSenders...)
+	}
+	return senders, nil
+}
@@ -0,0 +1,95 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/port"
+	"github.com/isutare412/crawlert/internal/core/port/mockport"
+)
+
+func newDestinationSender(t *testing.T, destination string) *mockport.MockMessageSender {
+	sender := mockport.NewMockMessageSender(t)
+	sender.EXPECT().Destination().Return(destination)
+	return sender
+}
+
+func Test_buildOutboundSenders(t *testing.T) {
+	build := func(destinations ...string) map[string]port.MessageSender {
+		senders := make([]port.MessageSender, 0, len(destinations))
+		for _, d := range destinations {
+			senders = append(senders, newDestinationSender(t, d))
+		}
+
+		sendersByKey := make(map[string]port.MessageSender)
+		for _, s := range buildOutboundSenders(map[string][]port.MessageSender{"ops": senders})["ops"] {
+			sendersByKey[s.key] = s.sender
+		}
+		return sendersByKey
+	}
+
+	before := build("chat-1", "chat-2", "chat-3")
+	after := build("chat-3", "chat-1")
+
+	// Keys of remaining destinations do not change after reordering.
+	for key, sender := range after {
+		require.Contains(t, before, key)
+		assert.Equal(t, before[key].Destination(), sender.Destination())
+	}
+	assert.Len(t, before, 3)
+	assert.Len(t, after, 2)
+}
+
+func Test_routeMessageSenders(t *testing.T) {
+	receivers := buildOutboundSenders(map[string][]port.MessageSender{
+		"ops":     {newDestinationSender(t, "chat-1"), newDestinationSender(t, "chat-2")},
+		"pricing": {newDestinationSender(t, "https://example.com/hook")},
+	})
+	var (
+		ops0    = outboxKey("ops", "chat-1")
+		ops1    = outboxKey("ops", "chat-2")
+		pricing = outboxKey("pricing", "https://example.com/hook")
+	)
+
+	tests := []struct {
+		name     string
+		names    []string
+		wantKeys []string
+		wantErr  bool
+	}{
+		{
+			name:     "routed_in_order",
+			names:    []string{"pricing", "ops"},
+			wantKeys: []string{pricing, ops0, ops1},
+		},
+		{
+			name:     "duplicate_names",
+			names:    []string{"ops", "pricing", "ops"},
+			wantKeys: []string{ops0, ops1, pricing},
+		},
+		{
+			name:    "unknown_receiver",
+			names:   []string{"ops", "finance"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			senders, err := routeMessageSenders(tt.names, receivers)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			keys := make([]string, 0, len(senders))
+			for _, s := range senders {
+				keys = append(keys, s.key)
+			}
+			assert.Equal(t, tt.wantKeys, keys)
+		})
+	}
+}
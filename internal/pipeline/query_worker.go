@@ -2,9 +2,17 @@ package pipeline
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/isutare412/crawlert/internal/core/domain"
 	"github.com/isutare412/crawlert/internal/core/port"
@@ -12,31 +20,94 @@ import (
 	"github.com/isutare412/crawlert/internal/query"
 )
 
+const (
+	variableNewItems     = "NEW_ITEMS"
+	variableRemovedItems = "REMOVED_ITEMS"
+	variableError        = "ERROR"
+	variableStatus       = "STATUS"
+	variableFailureCount = "FAILURE_COUNT"
+)
+
 type queryWorker struct {
 	applier      port.QueryApplier
+	input        QueryInput
+	collectItems bool
+	notifyOn     NotifyMode
+	sendResolved bool
+
+	// errorThreshold is the number of consecutive failures to alert. Failures
+	// are not alerted if zero.
+	errorThreshold int
+	sendRecovered  bool
+
+	state        *crawlState
 	crawlOutputs <-chan crawlOutput
 	queryOutputs chan<- queryOutput
 	wg           sync.WaitGroup
 }
 
 func newQueryWorker(
-	cfg CrawlQueryConfig,
+	cfg CrawlConfig,
+	state *crawlState,
 	crawlOutputs <-chan crawlOutput,
 	queryOutputs chan<- queryOutput,
 ) (*queryWorker, error) {
-	applier, err := query.NewApplier(cfg.Check, cfg.Variables)
+	applier, collectItems, err := newQueryApplier(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("creating query applier: %w", err)
 	}
 
+	var errorThreshold int
+	if cfg.OnError.Message != "" {
+		errorThreshold = max(cfg.OnError.Threshold, 1)
+	}
+
 	return &queryWorker{
-		applier:      applier,
-		crawlOutputs: crawlOutputs,
-		queryOutputs: queryOutputs,
-		wg:           sync.WaitGroup{},
+		applier:        applier,
+		input:          cfg.Query.Input,
+		collectItems:   collectItems,
+		notifyOn:       cfg.NotifyOn,
+		sendResolved:   cfg.ResolveMessage != "",
+		errorThreshold: errorThreshold,
+		sendRecovered:  cfg.OnError.Message != "" && cfg.OnError.RecoveryMessage != "",
+		state:          state,
+		crawlOutputs:   crawlOutputs,
+		queryOutputs:   queryOutputs,
+		wg:             sync.WaitGroup{},
 	}, nil
 }
 
+// newQueryApplier returns the query applier of the crawl. It reports whether
+// the applier collects items.
+func newQueryApplier(cfg CrawlConfig) (applier port.QueryApplier, collectItems bool, err error) {
+	if cfg.Query.Mode == QueryModeRegex {
+		applier, err := query.NewRegexApplier(query.RegexApplierConfig{
+			Check:     cfg.Query.Check,
+			Variables: cfg.Query.Variables,
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("creating regex applier: %w", err)
+		}
+		return applier, false, nil
+	}
+
+	applierCfg := query.ApplierConfig{
+		Check:     cfg.Query.Check,
+		Variables: cfg.Query.Variables,
+		Items:     cfg.Query.Items,
+		Key:       cfg.Query.Key,
+	}
+	if cfg.Target.Feed.URL != "" && applierCfg.Items == "" {
+		applierCfg.Items, applierCfg.Key = FeedQueries(cfg.Query.Input)
+	}
+
+	jqApplier, err := query.NewApplier(applierCfg)
+	if err != nil {
+		return nil, false, fmt.Errorf("creating jq applier: %w", err)
+	}
+	return jqApplier, applierCfg.Items != "", nil
+}
+
 func (w *queryWorker) run() {
 	w.wg.Add(1)
 	go func() {
@@ -46,18 +117,50 @@ func (w *queryWorker) run() {
 		for output := range w.crawlOutputs {
 			ctx := output.ctx
 
+			if output.err != nil {
+				w.handleCrawlFailure(ctx, output.err)
+				continue
+			}
+			w.handleCrawlSuccess(ctx)
+
 			queryResult, err := w.query(ctx, output.crawlResponse)
-			switch {
-			case err != nil:
+			if err != nil {
 				slog.ErrorContext(ctx, "failed to apply query", "error", err)
 				continue
+			}
+
+			changed, resolved, err := w.recordResult(ctx, output.crawlResponse, &queryResult)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to record query result", "error", err)
+
+				// New items cannot be told without state. Otherwise the query
+				// result is alerted as if it changed.
+				if w.collectItems {
+					continue
+				}
+				changed, resolved = true, false
+			}
+
+			switch {
+			case resolved && w.sendResolved:
+				w.queryOutputs <- queryOutput{
+					ctx:           ctx,
+					kind:          alertKindResolved,
+					crawlResponse: output.crawlResponse,
+					queryResult:   queryResult,
+				}
+				continue
 			case !queryResult.Matched:
 				slog.InfoContext(ctx, "query result does not matched")
 				continue
+			case w.notifyOn == NotifyOnChange && !changed:
+				slog.InfoContext(ctx, "query result not changed since last crawl")
+				continue
 			}
 
 			w.queryOutputs <- queryOutput{
 				ctx:           ctx,
+				kind:          alertKindMatched,
 				crawlResponse: output.crawlResponse,
 				queryResult:   queryResult,
 			}
@@ -69,11 +172,194 @@ func (w *queryWorker) shutdown() {
 	w.wg.Wait()
 }
 
+// handleCrawlFailure counts the failure and sends it to be alerted if the
+// consecutive failures reached the threshold. Failures are alerted again on the
+// next failure until the error message is sent.
+func (w *queryWorker) handleCrawlFailure(ctx context.Context, crawlErr error) {
+	var (
+		failures int
+		reached  bool
+	)
+	err := w.state.update(ctx, func(state *domain.CrawlState) {
+		state.ConsecutiveFailures++
+		failures = state.ConsecutiveFailures
+
+		reached = w.errorThreshold > 0 && !state.CrawlFailing && failures >= w.errorThreshold
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record crawl failure", "error", err)
+		return
+	}
+	if !reached {
+		return
+	}
+
+	var (
+		status    string
+		statusErr *domain.HTTPStatusError
+	)
+	if errors.As(crawlErr, &statusErr) {
+		status = strconv.Itoa(statusErr.StatusCode)
+	}
+
+	w.queryOutputs <- queryOutput{
+		ctx:  ctx,
+		kind: alertKindCrawlFailed,
+		queryResult: domain.QueryResult{
+			Variables: map[string]string{
+				variableError:        crawlErr.Error(),
+				variableStatus:       status,
+				variableFailureCount: strconv.Itoa(failures),
+			},
+		},
+	}
+}
+
+// handleCrawlSuccess resets the consecutive failures and sends recovery to be
+// alerted if the failures were alerted.
+func (w *queryWorker) handleCrawlSuccess(ctx context.Context) {
+	current, err := w.state.get(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get crawl state", "error", err)
+		return
+	}
+	if current.ConsecutiveFailures == 0 && !current.CrawlFailing {
+		return
+	}
+
+	var (
+		failures  int
+		recovered bool
+	)
+	err = w.state.update(ctx, func(state *domain.CrawlState) {
+		failures = state.ConsecutiveFailures
+		recovered = state.CrawlFailing
+
+		state.ConsecutiveFailures = 0
+		state.CrawlFailing = false
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record crawl success", "error", err)
+		return
+	}
+	if !recovered || !w.sendRecovered {
+		return
+	}
+
+	w.queryOutputs <- queryOutput{
+		ctx:  ctx,
+		kind: alertKindCrawlRecovered,
+		queryResult: domain.QueryResult{
+			Variables: map[string]string{
+				variableFailureCount: strconv.Itoa(failures),
+			},
+		},
+	}
+}
+
 func (w *queryWorker) query(ctx context.Context, crawlResp domain.CrawlResponse) (domain.QueryResult, error) {
-	result, err := w.applier.ApplyQuery(crawlResp.Body)
+	input := crawlResp.Body
+	if w.input == QueryInputEnvelope {
+		envelope, err := buildEnvelope(crawlResp)
+		if err != nil {
+			return domain.QueryResult{}, fmt.Errorf("building envelope: %w", err)
+		}
+		input = envelope
+	}
+
+	result, err := w.applier.ApplyQuery(input)
 	if err != nil {
 		return domain.QueryResult{}, fmt.Errorf("applying query: %w", err)
 	}
 
 	return result, nil
 }
+
+// recordResult saves the crawl response and query result into the crawl state.
+// If items are collected, queryResult is updated to match only if new items
+// appeared since the previous crawl. It reports whether the query result
+// differs from the one of the previous crawl, and whether the query stopped
+// matching after a message was sent for it.
+func (w *queryWorker) recordResult(
+	ctx context.Context,
+	crawlResp domain.CrawlResponse,
+	queryResult *domain.QueryResult,
+) (changed, resolved bool, err error) {
+	changed = true
+	err = w.state.update(ctx, func(state *domain.CrawlState) {
+		if w.collectItems {
+			newItems, removedItems := diffItems(state.SeenItems, queryResult.Items)
+			queryResult.Variables[variableNewItems] = encodeItems(newItems)
+			queryResult.Variables[variableRemovedItems] = encodeItems(removedItems)
+			queryResult.Matched = queryResult.Matched && len(newItems) > 0
+
+			state.SeenItems = make(map[string]string, len(queryResult.Items))
+			for _, item := range queryResult.Items {
+				state.SeenItems[item.Key] = item.Value
+			}
+		}
+
+		if state.LastQueryResult != nil {
+			changed = !isSameQueryResult(*state.LastQueryResult, *queryResult)
+		}
+
+		// Matched of items mode reports only whether new items appeared, so
+		// it never resolves.
+		resolved = !w.collectItems && state.Firing && !queryResult.Matched
+
+		state.LastResponseHash = hashBytes(crawlResp.Body)
+		state.LastQueryResult = queryResult
+		if queryResult.Matched {
+			state.LastMatchedAt = time.Now()
+		} else {
+			state.Firing = false
+		}
+	})
+	if err != nil {
+		return false, false, fmt.Errorf("updating crawl state: %w", err)
+	}
+
+	return changed, resolved, nil
+}
+
+func hashBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func isSameQueryResult(a, b domain.QueryResult) bool {
+	return a.Matched == b.Matched && maps.Equal(a.Variables, b.Variables)
+}
+
+// diffItems returns items not in seen and seen items not in items. Nothing is
+// reported if seen is nil, because there is no previous crawl to compare with.
+func diffItems(seen map[string]string, items []domain.QueryItem) (newItems, removedItems []domain.QueryItem) {
+	if seen == nil {
+		return nil, nil
+	}
+
+	current := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		current[item.Key] = struct{}{}
+		if _, ok := seen[item.Key]; !ok {
+			newItems = append(newItems, item)
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(seen)) {
+		if _, ok := current[key]; !ok {
+			removedItems = append(removedItems, domain.QueryItem{Key: key, Value: seen[key]})
+		}
+	}
+
+	return newItems, removedItems
+}
+
+// encodeItems encodes items into a JSON array.
+func encodeItems(items []domain.QueryItem) string {
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		values = append(values, item.Value)
+	}
+	return "[" + strings.Join(values, ",") + "]"
+}
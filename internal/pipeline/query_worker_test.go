@@ -0,0 +1,306 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/memory"
+)
+
+func Test_isSameQueryResult(t *testing.T) {
+	type args struct {
+		a domain.QueryResult
+		b domain.QueryResult
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "same_result",
+			args: args{
+				a: domain.QueryResult{Matched: true, Variables: map[string]string{"ONE": `["a"]`}},
+				b: domain.QueryResult{Matched: true, Variables: map[string]string{"ONE": `["a"]`}},
+			},
+			want: true,
+		},
+		{
+			name: "check_outcome_changed",
+			args: args{
+				a: domain.QueryResult{Matched: false, Variables: map[string]string{"ONE": `["a"]`}},
+				b: domain.QueryResult{Matched: true, Variables: map[string]string{"ONE": `["a"]`}},
+			},
+			want: false,
+		},
+		{
+			name: "variable_changed",
+			args: args{
+				a: domain.QueryResult{Matched: true, Variables: map[string]string{"ONE": `["a"]`}},
+				b: domain.QueryResult{Matched: true, Variables: map[string]string{"ONE": `["a","b"]`}},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := isSameQueryResult(tt.args.a, tt.args.b)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_diffItems(t *testing.T) {
+	type args struct {
+		seen  map[string]string
+		items []domain.QueryItem
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantNew     []domain.QueryItem
+		wantRemoved []domain.QueryItem
+	}{
+		{
+			name: "first_crawl",
+			args: args{
+				seen:  nil,
+				items: []domain.QueryItem{{Key: "1", Value: `{"id":1}`}},
+			},
+		},
+		{
+			name: "new_and_removed_items",
+			args: args{
+				seen: map[string]string{
+					"1": `{"id":1}`,
+					"2": `{"id":2}`,
+					"3": `{"id":3}`,
+				},
+				items: []domain.QueryItem{
+					{Key: "4", Value: `{"id":4}`},
+					{Key: "2", Value: `{"id":2}`},
+				},
+			},
+			wantNew: []domain.QueryItem{{Key: "4", Value: `{"id":4}`}},
+			wantRemoved: []domain.QueryItem{
+				{Key: "1", Value: `{"id":1}`},
+				{Key: "3", Value: `{"id":3}`},
+			},
+		},
+		{
+			name: "nothing_seen_before",
+			args: args{
+				seen:  map[string]string{},
+				items: []domain.QueryItem{{Key: "1", Value: `{"id":1}`}},
+			},
+			wantNew: []domain.QueryItem{{Key: "1", Value: `{"id":1}`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotNew, gotRemoved := diffItems(tt.args.seen, tt.args.items)
+			assert.Equal(t, tt.wantNew, gotNew)
+			assert.Equal(t, tt.wantRemoved, gotRemoved)
+		})
+	}
+}
+
+func Test_queryWorker_recordResult(t *testing.T) {
+	type result struct {
+		matched      bool
+		sent         bool
+		wantChanged  bool
+		wantResolved bool
+	}
+	tests := []struct {
+		name    string
+		results []result
+	}{
+		{
+			name: "firing_and_resolved",
+			results: []result{
+				{matched: false, wantChanged: true, wantResolved: false},
+				{matched: true, sent: true, wantChanged: true, wantResolved: false},
+				{matched: true, wantChanged: false, wantResolved: false},
+				{matched: false, wantChanged: true, wantResolved: true},
+				{matched: false, wantChanged: false, wantResolved: false},
+			},
+		},
+		{
+			name: "not_resolved_if_message_not_sent",
+			results: []result{
+				{matched: true, sent: false, wantChanged: true, wantResolved: false},
+				{matched: false, wantChanged: true, wantResolved: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			w, err := newQueryWorker(
+				CrawlConfig{Name: "test", Query: CrawlQueryConfig{Check: "true"}},
+				newCrawlState("test", memory.NewStore()), nil, nil)
+			require.NoError(t, err)
+
+			for i, r := range tt.results {
+				queryResult := domain.QueryResult{Matched: r.matched, Variables: map[string]string{}}
+				gotChanged, gotResolved, err := w.recordResult(ctx, domain.CrawlResponse{}, &queryResult)
+				require.NoError(t, err)
+				assert.Equal(t, r.wantChanged, gotChanged, "changed of result %d", i)
+				assert.Equal(t, r.wantResolved, gotResolved, "resolved of result %d", i)
+
+				// Message worker marks firing once the message is sent.
+				if r.sent {
+					require.NoError(t, w.state.update(ctx, func(state *domain.CrawlState) { state.Firing = true }))
+				}
+			}
+		})
+	}
+}
+
+func Test_queryWorker_handleCrawlResult(t *testing.T) {
+	statusErr := fmt.Errorf("crawling http: %w", &domain.HTTPStatusError{
+		StatusCode: 503,
+		Status:     "503 Service Unavailable",
+	})
+
+	ctx := context.Background()
+	queryOutputs := make(chan queryOutput, 10)
+	w, err := newQueryWorker(
+		CrawlConfig{
+			Name:  "test",
+			Query: CrawlQueryConfig{Check: "true"},
+			OnError: OnErrorConfig{
+				Threshold:       2,
+				Message:         "failed",
+				RecoveryMessage: "recovered",
+			},
+		},
+		newCrawlState("test", memory.NewStore()), nil, queryOutputs)
+	require.NoError(t, err)
+
+	// Failures are alerted when reaching the threshold.
+	w.handleCrawlFailure(ctx, statusErr)
+	assert.Empty(t, queryOutputs)
+	w.handleCrawlFailure(ctx, statusErr)
+	require.Len(t, queryOutputs, 1)
+
+	output := <-queryOutputs
+	assert.Equal(t, alertKindCrawlFailed, output.kind)
+	assert.Equal(t, map[string]string{
+		variableError:        statusErr.Error(),
+		variableStatus:       "503",
+		variableFailureCount: "2",
+	}, output.queryResult.Variables)
+
+	// Failures are alerted again until the error message is sent, which
+	// message worker records.
+	w.handleCrawlFailure(ctx, errors.New("connection refused"))
+	require.Len(t, queryOutputs, 1)
+	<-queryOutputs
+	require.NoError(t, w.state.update(ctx, func(state *domain.CrawlState) {
+		state.CrawlFailing = true
+	}))
+	w.handleCrawlFailure(ctx, errors.New("connection refused"))
+	assert.Empty(t, queryOutputs)
+
+	// Recovery is alerted once.
+	w.handleCrawlSuccess(ctx)
+	w.handleCrawlSuccess(ctx)
+	require.Len(t, queryOutputs, 1)
+
+	output = <-queryOutputs
+	assert.Equal(t, alertKindCrawlRecovered, output.kind)
+	assert.Equal(t, map[string]string{variableFailureCount: "4"}, output.queryResult.Variables)
+
+	// Failures below the threshold are neither alerted nor recovered.
+	w.handleCrawlFailure(ctx, statusErr)
+	w.handleCrawlSuccess(ctx)
+	assert.Empty(t, queryOutputs)
+}
+
+func Test_queryWorker_feedEntries(t *testing.T) {
+	tests := []struct {
+		name  string
+		input QueryInput
+	}{
+		{name: "body", input: QueryInputBody},
+		{name: "envelope", input: QueryInputEnvelope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			w, err := newQueryWorker(
+				CrawlConfig{
+					Name:   "test",
+					Target: CrawlTargetConfig{Feed: CrawlFeedTargetConfig{URL: "https://foo.com/feed"}},
+					Query:  CrawlQueryConfig{Input: tt.input},
+				},
+				newCrawlState("test", memory.NewStore()), nil, nil)
+			require.NoError(t, err)
+
+			crawl := func(entries string) domain.QueryResult {
+				resp := domain.CrawlResponse{StatusCode: 200, Body: []byte(entries)}
+				queryResult, err := w.query(ctx, resp)
+				require.NoError(t, err)
+				_, _, err = w.recordResult(ctx, resp, &queryResult)
+				require.NoError(t, err)
+				return queryResult
+			}
+
+			// Entries of the first crawl are the baseline.
+			got := crawl(`[{"id":"1","title":"first"}]`)
+			assert.False(t, got.Matched)
+
+			got = crawl(`[{"id":"2","title":"second"},{"id":"1","title":"first"}]`)
+			assert.True(t, got.Matched)
+			assert.JSONEq(t, `[{"id":"2","title":"second"}]`, got.Variables[variableNewItems])
+
+			got = crawl(`[{"id":"2","title":"second"},{"id":"1","title":"first"}]`)
+			assert.False(t, got.Matched)
+		})
+	}
+}
+
+func Test_queryWorker_itemsNeverResolve(t *testing.T) {
+	ctx := context.Background()
+	w, err := newQueryWorker(
+		CrawlConfig{
+			Name:           "test",
+			Query:          CrawlQueryConfig{Items: ".", Key: ".id"},
+			ResolveMessage: "resolved",
+		},
+		newCrawlState("test", memory.NewStore()), nil, nil)
+	require.NoError(t, err)
+
+	crawl := func(items string) (matched, resolved bool) {
+		resp := domain.CrawlResponse{Body: []byte(items)}
+		queryResult, err := w.query(ctx, resp)
+		require.NoError(t, err)
+		_, resolved, err = w.recordResult(ctx, resp, &queryResult)
+		require.NoError(t, err)
+		return queryResult.Matched, resolved
+	}
+
+	crawl(`[{"id":1}]`)
+
+	// New items are alerted.
+	matched, resolved := crawl(`[{"id":1},{"id":2}]`)
+	assert.True(t, matched)
+	assert.False(t, resolved)
+	require.NoError(t, w.state.update(ctx, func(state *domain.CrawlState) { state.Firing = true }))
+
+	// No new items do not resolve the alert of former new items.
+	matched, resolved = crawl(`[{"id":1},{"id":2}]`)
+	assert.False(t, matched)
+	assert.False(t, resolved)
+}
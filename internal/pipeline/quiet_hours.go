@@ -0,0 +1,182 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietMode decides what to do with messages produced during quiet hours.
+type QuietMode string
+
+const (
+	// QuietModeDiscard drops messages produced during quiet hours.
+	QuietModeDiscard QuietMode = "discard"
+	// QuietModeDigest holds messages produced during quiet hours and sends
+	// them as a digest when quiet hours end.
+	QuietModeDigest QuietMode = "digest"
+)
+
+func (m QuietMode) Validate() error {
+	switch m {
+	case "", QuietModeDiscard, QuietModeDigest:
+		return nil
+	default:
+		return fmt.Errorf("unknown quiet mode '%s'", m)
+	}
+}
+
+type QuietHoursConfig struct {
+	Mode     QuietMode
+	Timezone string
+	Windows  []QuietWindowConfig
+}
+
+func (c QuietHoursConfig) Validate() error {
+	if _, err := newQuietHours(c); err != nil {
+		return err
+	}
+	return nil
+}
+
+// QuietWindowConfig is a time window of a day. Start and end are formatted as
+// "15:04". The window continues to the next day if end is not after start.
+type QuietWindowConfig struct {
+	// Days are the weekdays on which the window starts. Every day is used if
+	// empty.
+	Days  []string
+	Start string
+	End   string
+}
+
+type quietHours struct {
+	mode     QuietMode
+	location *time.Location
+	windows  []quietWindow
+}
+
+type quietWindow struct {
+	days  map[time.Weekday]struct{} // nil if every day
+	start time.Duration
+	end   time.Duration
+}
+
+// newQuietHours returns nil if cfg has no window.
+func newQuietHours(cfg QuietHoursConfig) (*quietHours, error) {
+	if len(cfg.Windows) == 0 {
+		return nil, nil
+	}
+
+	if err := cfg.Mode.Validate(); err != nil {
+		return nil, fmt.Errorf("validating mode: %w", err)
+	}
+	mode := cfg.Mode
+	if mode == "" {
+		mode = QuietModeDiscard
+	}
+
+	location := time.Local
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("loading timezone %s: %w", cfg.Timezone, err)
+		}
+		location = loc
+	}
+
+	windows := make([]quietWindow, 0, len(cfg.Windows))
+	for i, wc := range cfg.Windows {
+		window, err := newQuietWindow(wc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing window %d: %w", i, err)
+		}
+		windows = append(windows, window)
+	}
+
+	return &quietHours{
+		mode:     mode,
+		location: location,
+		windows:  windows,
+	}, nil
+}
+
+func newQuietWindow(cfg QuietWindowConfig) (quietWindow, error) {
+	start, err := parseTimeOfDay(cfg.Start)
+	if err != nil {
+		return quietWindow{}, fmt.Errorf("parsing start: %w", err)
+	}
+	end, err := parseTimeOfDay(cfg.End)
+	if err != nil {
+		return quietWindow{}, fmt.Errorf("parsing end: %w", err)
+	}
+
+	var days map[time.Weekday]struct{}
+	if len(cfg.Days) > 0 {
+		days = make(map[time.Weekday]struct{}, len(cfg.Days))
+		for _, d := range cfg.Days {
+			weekday, err := parseWeekday(d)
+			if err != nil {
+				return quietWindow{}, err
+			}
+			days[weekday] = struct{}{}
+		}
+	}
+
+	return quietWindow{
+		days:  days,
+		start: start,
+		end:   end,
+	}, nil
+}
+
+// isQuiet reports whether t is in any of the windows.
+func (q *quietHours) isQuiet(t time.Time) bool {
+	t = t.In(q.location)
+	for _, w := range q.windows {
+		if w.contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w quietWindow) contains(t time.Time) bool {
+	sinceMidnight := time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+
+	if w.start < w.end {
+		return w.startsOn(t.Weekday()) && sinceMidnight >= w.start && sinceMidnight < w.end
+	}
+
+	// The window continues to the next day.
+	yesterday := t.AddDate(0, 0, -1).Weekday()
+	return (w.startsOn(t.Weekday()) && sinceMidnight >= w.start) ||
+		(w.startsOn(yesterday) && sinceMidnight < w.end)
+}
+
+func (w quietWindow) startsOn(day time.Weekday) bool {
+	if w.days == nil {
+		return true
+	}
+	_, ok := w.days[day]
+	return ok
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("parsing time of day '%s': %w", s, err)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func parseWeekday(s string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := d.String()
+		if strings.EqualFold(s, name) || strings.EqualFold(s, name[:3]) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday '%s'", s)
+}
@@ -0,0 +1,113 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_quietHours_isQuiet(t *testing.T) {
+	cfg := QuietHoursConfig{
+		Mode:     QuietModeDigest,
+		Timezone: "Asia/Seoul",
+		Windows: []QuietWindowConfig{
+			{Start: "22:00", End: "08:00"},
+			{Days: []string{"sat", "Sunday"}, Start: "00:00", End: "00:00"},
+		},
+	}
+	kst := time.FixedZone("KST", 9*60*60)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{
+			name: "weekday_daytime",
+			t:    time.Date(2024, 10, 2, 12, 0, 0, 0, kst), // Wed
+			want: false,
+		},
+		{
+			name: "weekday_night",
+			t:    time.Date(2024, 10, 2, 23, 0, 0, 0, kst),
+			want: true,
+		},
+		{
+			name: "weekday_early_morning",
+			t:    time.Date(2024, 10, 3, 7, 59, 0, 0, kst),
+			want: true,
+		},
+		{
+			name: "window_end_is_exclusive",
+			t:    time.Date(2024, 10, 3, 8, 0, 0, 0, kst),
+			want: false,
+		},
+		{
+			name: "whole_weekend",
+			t:    time.Date(2024, 10, 6, 15, 0, 0, 0, kst), // Sun
+			want: true,
+		},
+		{
+			name: "other_timezone",
+			t:    time.Date(2024, 10, 2, 14, 0, 0, 0, time.UTC), // Wed 23:00 KST
+			want: true,
+		},
+	}
+
+	q, err := newQuietHours(cfg)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, q.isQuiet(tt.t))
+		})
+	}
+}
+
+func TestQuietHoursConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     QuietHoursConfig
+		wantErr bool
+	}{
+		{
+			name: "no_window",
+			cfg:  QuietHoursConfig{},
+		},
+		{
+			name: "unknown_mode",
+			cfg: QuietHoursConfig{
+				Mode:    "hold",
+				Windows: []QuietWindowConfig{{Start: "22:00", End: "08:00"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_time",
+			cfg: QuietHoursConfig{
+				Windows: []QuietWindowConfig{{Start: "22", End: "08:00"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown_weekday",
+			cfg: QuietHoursConfig{
+				Windows: []QuietWindowConfig{{Days: []string{"someday"}, Start: "22:00", End: "08:00"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/core/port"
+)
+
+// crawlState serializes read-modify-write of the persisted state of a crawl,
+// which is shared by workers of the same worker group.
+type crawlState struct {
+	crawlName string
+	store     port.StateStore
+	mu        sync.Mutex
+}
+
+func newCrawlState(crawlName string, store port.StateStore) *crawlState {
+	return &crawlState{
+		crawlName: crawlName,
+		store:     store,
+		mu:        sync.Mutex{},
+	}
+}
+
+func (s *crawlState) get(ctx context.Context) (domain.CrawlState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.store.GetCrawlState(ctx, s.crawlName)
+	if err != nil {
+		return domain.CrawlState{}, fmt.Errorf("getting crawl state: %w", err)
+	}
+	return state, nil
+}
+
+// update applies fn to the stored state and saves the modified state.
+func (s *crawlState) update(ctx context.Context, fn func(*domain.CrawlState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.store.GetCrawlState(ctx, s.crawlName)
+	if err != nil {
+		return fmt.Errorf("getting crawl state: %w", err)
+	}
+
+	fn(&state)
+
+	if err := s.store.PutCrawlState(ctx, s.crawlName, state); err != nil {
+		return fmt.Errorf("putting crawl state: %w", err)
+	}
+	return nil
+}
@@ -2,17 +2,37 @@ package pipeline
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/cron"
+	"github.com/isutare412/crawlert/internal/graphql"
 	"github.com/isutare412/crawlert/internal/log"
 )
 
+// schedule computes when to trigger crawls.
+type schedule interface {
+	// Next returns the first fire time after t, or zero time if it never
+	// fires again.
+	Next(t time.Time) time.Time
+}
+
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
 type triggerWorker struct {
 	jobName        string
-	interval       time.Duration
+	schedule       schedule
+	triggerOnStart bool
 	crawlRequest   domain.CrawlRequest
 	triggerOutputs chan<- triggerOutput
 
@@ -21,18 +41,37 @@ type triggerWorker struct {
 	wg             sync.WaitGroup
 }
 
-func newTriggerWorker(cfg CrawlConfig, triggerOutputs chan<- triggerOutput) *triggerWorker {
+func newTriggerWorker(cfg CrawlConfig, triggerOutputs chan<- triggerOutput) (*triggerWorker, error) {
+	var (
+		sched          schedule = intervalSchedule{interval: cfg.Interval}
+		triggerOnStart          = true
+	)
+	if cfg.Schedule != "" {
+		s, err := cron.Parse(cfg.Schedule, cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("parsing schedule: %w", err)
+		}
+		sched = s
+		triggerOnStart = false
+	}
+
+	crawlRequest, err := buildCrawlRequest(cfg.Target, cfg.Query.Input)
+	if err != nil {
+		return nil, fmt.Errorf("building crawl request: %w", err)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &triggerWorker{
 		jobName:        cfg.Name,
-		interval:       cfg.Interval,
-		crawlRequest:   buildCrawlRequest(cfg.Target.HTTP),
+		schedule:       sched,
+		triggerOnStart: triggerOnStart,
+		crawlRequest:   crawlRequest,
 		triggerOutputs: triggerOutputs,
 		lifetimeCtx:    ctx,
 		lifetimeCancel: cancel,
 		wg:             sync.WaitGroup{},
-	}
+	}, nil
 }
 
 func (w *triggerWorker) run() {
@@ -41,11 +80,28 @@ func (w *triggerWorker) run() {
 		defer w.wg.Done()
 		defer log.RecoverIfPanic()
 
-		for {
+		if w.triggerOnStart {
 			w.trigger()
+		}
+
+		// Next fire time is computed from the previous fire time rather than
+		// the current time, so that triggers do not drift.
+		fireAt := time.Now()
+		for {
+			now := time.Now()
+			fireAt = w.schedule.Next(fireAt)
+			if fireAt.Before(now) {
+				fireAt = w.schedule.Next(now)
+			}
+			if fireAt.IsZero() {
+				ctx := log.WithValue(context.Background(), "jobName", w.jobName)
+				slog.ErrorContext(ctx, "stopped triggering crawls as schedule never fires again")
+				return
+			}
 
 			select {
-			case <-time.After(w.interval):
+			case <-time.After(time.Until(fireAt)):
+				w.trigger()
 			case <-w.lifetimeCtx.Done():
 				return
 			}
@@ -68,13 +124,55 @@ func (w *triggerWorker) trigger() {
 	}
 }
 
-func buildCrawlRequest(cfg CrawlHTTPTargetConfig) domain.CrawlRequest {
-	return domain.CrawlRequest{
-		URL:    cfg.URL,
-		Method: cfg.Method,
-		Header: buildHTTPHeader(cfg.Header),
-		Body:   []byte(cfg.Body),
+// feedAccept is the default Accept header of feed requests.
+const feedAccept = "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.9, */*;q=0.8"
+
+func buildCrawlRequest(cfg CrawlTargetConfig, input QueryInput) (domain.CrawlRequest, error) {
+	allowErrorStatus := input == QueryInputEnvelope
+
+	switch {
+	case cfg.Feed.URL != "":
+		header := http.Header{}
+		header.Set("Accept", feedAccept)
+		for k, v := range cfg.Feed.Header {
+			header.Set(k, v)
+		}
+
+		return domain.CrawlRequest{
+			URL:              cfg.Feed.URL,
+			Method:           http.MethodGet,
+			Header:           header,
+			AllowErrorStatus: allowErrorStatus,
+		}, nil
+	case cfg.GraphQL.Endpoint != "":
+		body, err := graphql.BuildRequestBody(cfg.GraphQL.Query, cfg.GraphQL.Variables, cfg.GraphQL.OperationName)
+		if err != nil {
+			return domain.CrawlRequest{}, fmt.Errorf("building graphql request body: %w", err)
+		}
+
+		header := http.Header{}
+		header.Set("Content-Type", "application/json")
+		header.Set("Accept", "application/graphql-response+json, application/json;q=0.9")
+		for k, v := range cfg.GraphQL.Header {
+			header.Set(k, v)
+		}
+
+		return domain.CrawlRequest{
+			URL:              cfg.GraphQL.Endpoint,
+			Method:           http.MethodPost,
+			Header:           header,
+			Body:             body,
+			AllowErrorStatus: allowErrorStatus,
+		}, nil
 	}
+
+	return domain.CrawlRequest{
+		URL:              cfg.HTTP.URL,
+		Method:           cfg.HTTP.Method,
+		Header:           buildHTTPHeader(cfg.HTTP.Header),
+		Body:             []byte(cfg.HTTP.Body),
+		AllowErrorStatus: allowErrorStatus,
+	}, nil
 }
 
 func buildHTTPHeader(h map[string]string) http.Header {
@@ -0,0 +1,43 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type neverSchedule struct{}
+
+func (neverSchedule) Next(time.Time) time.Time {
+	return time.Time{}
+}
+
+func Test_triggerWorker_run_scheduleNeverFires(t *testing.T) {
+	triggerOutputs := make(chan triggerOutput, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := &triggerWorker{
+		jobName:        "test",
+		schedule:       neverSchedule{},
+		triggerOutputs: triggerOutputs,
+		lifetimeCtx:    ctx,
+		lifetimeCancel: cancel,
+	}
+	w.run()
+
+	stopped := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("trigger worker did not stop")
+	}
+	assert.Empty(t, triggerOutputs)
+}
@@ -3,7 +3,9 @@ package pipeline
 import (
 	"fmt"
 
+	"github.com/isutare412/crawlert/internal/auth"
 	"github.com/isutare412/crawlert/internal/core/port"
+	"github.com/isutare412/crawlert/internal/signing"
 )
 
 type workerGroup struct {
@@ -20,7 +22,10 @@ type workerGroup struct {
 func newWorkerGroup(
 	cfg CrawlConfig,
 	httpCrawler port.HTTPCrawler,
-	messageSenders []port.MessageSender,
+	authProvider *auth.Provider,
+	messageSenders []outboundSender,
+	stateStore port.StateStore,
+	outbox port.Outbox,
 ) (*workerGroup, error) {
 	var (
 		triggerOutputs = make(chan triggerOutput, 1)
@@ -28,15 +33,50 @@ func newWorkerGroup(
 		queryOutputs   = make(chan queryOutput, 1)
 	)
 
-	triggerWorker := newTriggerWorker(cfg, triggerOutputs)
-	crawlWorker := newCrawlWorker(httpCrawler, triggerOutputs, crawlOutputs)
+	state := newCrawlState(cfg.Name, stateStore)
 
-	queryWorker, err := newQueryWorker(cfg.Query, crawlOutputs, queryOutputs)
+	triggerWorker, err := newTriggerWorker(cfg, triggerOutputs)
+	if err != nil {
+		return nil, fmt.Errorf("creating trigger worker: %w", err)
+	}
+
+	decoder, err := newResponseDecoder(cfg.Target)
+	if err != nil {
+		return nil, fmt.Errorf("creating response decoder: %w", err)
+	}
+
+	paginator, err := newPaginator(cfg.Target.HTTP.Pagination)
+	if err != nil {
+		return nil, fmt.Errorf("creating paginator: %w", err)
+	}
+
+	workflow, err := newWorkflow(cfg.Target.Steps, cfg.Query.Input == QueryInputEnvelope)
+	if err != nil {
+		return nil, fmt.Errorf("creating workflow: %w", err)
+	}
+
+	authenticator, err := authProvider.NewAuthenticator(cfg.Target.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("creating authenticator: %w", err)
+	}
+
+	signer, err := signing.NewSigner(cfg.Target.Signing)
+	if err != nil {
+		return nil, fmt.Errorf("creating request signer: %w", err)
+	}
+
+	crawlWorker := newCrawlWorker(
+		httpCrawler, decoder, paginator, workflow, authenticator, signer, triggerOutputs, crawlOutputs)
+
+	queryWorker, err := newQueryWorker(cfg, state, crawlOutputs, queryOutputs)
 	if err != nil {
 		return nil, fmt.Errorf("creating query worker: %w", err)
 	}
 
-	messageWorker := newMessageWorker(cfg.Message, messageSenders, queryOutputs)
+	messageWorker, err := newMessageWorker(cfg, messageSenders, outbox, state, queryOutputs)
+	if err != nil {
+		return nil, fmt.Errorf("creating message worker: %w", err)
+	}
 
 	return &workerGroup{
 		trigger:        triggerWorker,
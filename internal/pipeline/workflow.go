@@ -0,0 +1,252 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/query"
+	"github.com/isutare412/crawlert/internal/template"
+)
+
+// variableItem is the variable of each item of for-each steps.
+const variableItem = "ITEM"
+
+// regexPatternWholeVariable matches a template which is a sole variable, such
+// as a URL extracted by an earlier step.
+var regexPatternWholeVariable = regexp.MustCompile(`^\$(?:\{\w+\}|\w+)$`)
+
+// workflow crawls steps of a target in order. Requests of each step are
+// rendered with variables extracted from the results of earlier steps, and
+// results of the steps are combined into a JSON object keyed by step names.
+type workflow struct {
+	steps []workflowStep
+}
+
+type workflowStep struct {
+	name    string
+	request domain.CrawlRequest
+	extract map[string]*query.Expression
+	forEach string
+}
+
+// newWorkflow returns nil if no steps are configured. Responses of the last step
+// with status >= 400 succeed if allowErrorStatus is true, so that the status is
+// available to queries.
+func newWorkflow(cfgs []CrawlStepConfig, allowErrorStatus bool) (*workflow, error) {
+	if len(cfgs) == 0 {
+		return nil, nil
+	}
+
+	steps := make([]workflowStep, 0, len(cfgs))
+	for i, cfg := range cfgs {
+		extract := make(map[string]*query.Expression, len(cfg.Extract))
+		for key, q := range cfg.Extract {
+			expr, err := query.NewExpression(q)
+			if err != nil {
+				return nil, fmt.Errorf("creating extract query %s of step %s: %w", key, cfg.Name, err)
+			}
+			extract[key] = expr
+		}
+
+		steps = append(steps, workflowStep{
+			name: cfg.Name,
+			request: domain.CrawlRequest{
+				URL:    cfg.URL,
+				Method: cfg.Method,
+				Header: buildHTTPHeader(cfg.Header),
+				Body:   []byte(cfg.Body),
+
+				AllowErrorStatus: allowErrorStatus && i == len(cfgs)-1,
+			},
+			extract: extract,
+			forEach: cfg.ForEach,
+		})
+	}
+
+	return &workflow{steps: steps}, nil
+}
+
+// run crawls every step with crawlPage. The returned response is the one of
+// the last request, of which body is replaced with the combined results.
+func (wf *workflow) run(ctx context.Context, crawlPage crawlPageFunc) (domain.CrawlResponse, error) {
+	var (
+		variables = make(map[string]string)
+		results   = make(map[string]any, len(wf.steps))
+		resp      domain.CrawlResponse
+		duration  time.Duration
+	)
+	for _, step := range wf.steps {
+		var result any
+		if step.forEach == "" {
+			stepResp, doc, err := step.crawl(ctx, crawlPage, variables)
+			if err != nil {
+				return domain.CrawlResponse{}, fmt.Errorf("crawling step %s: %w", step.name, err)
+			}
+			resp, result = stepResp, doc
+			duration += stepResp.Duration
+		} else {
+			var items []any
+			if err := decodeJSON([]byte(variables[step.forEach]), &items); err != nil {
+				return domain.CrawlResponse{}, fmt.Errorf("for-each variable %s of step %s should be an array: %w",
+					step.forEach, step.name, err)
+			}
+
+			docs := make([]any, 0, len(items))
+			for i, item := range items {
+				itemVariables := maps.Clone(variables)
+				itemVariables[variableItem] = stringifyValue(item)
+
+				stepResp, doc, err := step.crawl(ctx, crawlPage, itemVariables)
+				if err != nil {
+					return domain.CrawlResponse{}, fmt.Errorf("crawling item %d of step %s: %w", i, step.name, err)
+				}
+				resp = stepResp
+				duration += stepResp.Duration
+				docs = append(docs, doc)
+			}
+			result = docs
+		}
+		results[step.name] = result
+
+		for key, expr := range step.extract {
+			value, ok, err := expr.EvaluateValue(result)
+			if err != nil {
+				return domain.CrawlResponse{}, fmt.Errorf("extracting %s of step %s: %w", key, step.name, err)
+			}
+			if !ok {
+				value = ""
+			}
+			variables[key] = stringifyValue(value)
+		}
+	}
+
+	body, err := json.Marshal(results)
+	if err != nil {
+		return domain.CrawlResponse{}, fmt.Errorf("marshaling step results: %w", err)
+	}
+	resp.Body = body
+	resp.Duration = duration
+
+	return resp, nil
+}
+
+// crawl crawls the request of the step rendered with variables. Values of
+// variables are URL-escaped in the URL and form bodies, unless a variable is
+// the whole URL. In other bodies, values of valid JSON are placed as they are
+// and others are JSON-escaped. The response body is decoded as JSON if valid,
+// or as a string otherwise.
+func (s workflowStep) crawl(ctx context.Context, crawlPage crawlPageFunc, variables map[string]string) (
+	domain.CrawlResponse, any, error,
+) {
+	header := make(http.Header, len(s.request.Header))
+	for k, values := range s.request.Header {
+		for _, v := range values {
+			header.Add(k, template.Render(v, variables))
+		}
+	}
+
+	escapeBody := escapeJSONValue
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if mediaType == "application/x-www-form-urlencoded" {
+		escapeBody = escapeURLValue
+	}
+
+	escapeURL := escapeURLValue
+	if regexPatternWholeVariable.MatchString(strings.TrimSpace(s.request.URL)) {
+		escapeURL = func(v string) string { return v }
+	}
+
+	resp, err := crawlPage(ctx, domain.CrawlRequest{
+		URL:    renderEscaped(s.request.URL, variables, escapeURL),
+		Method: s.request.Method,
+		Header: header,
+		Body:   []byte(renderEscaped(string(s.request.Body), variables, escapeBody)),
+
+		AllowErrorStatus: s.request.AllowErrorStatus,
+	})
+	if err != nil {
+		return domain.CrawlResponse{}, nil, err
+	}
+
+	var doc any
+	if err := decodeJSON(resp.Body, &doc); err != nil {
+		doc = string(resp.Body)
+	}
+	return resp, doc, nil
+}
+
+// renderEscaped is like [template.Render], but values of variables are escaped
+// with escape.
+func renderEscaped(tmpl string, variables map[string]string, escape func(string) string) string {
+	return template.RenderFunc(tmpl, func(key string) (string, bool) {
+		value, ok := variables[key]
+		if !ok {
+			return "", false
+		}
+		return escape(value), true
+	})
+}
+
+// escapeURLValue escapes s to be placed in either path or query of a URL.
+func escapeURLValue(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// escapeJSONValue returns s as it is if s is a valid JSON, such as an array
+// extracted by an earlier step, or JSON-escapes s otherwise.
+func escapeJSONValue(s string) string {
+	if json.Valid([]byte(s)) {
+		return s
+	}
+	return escapeJSONString(s)
+}
+
+// escapeJSONString escapes s to be placed inside a JSON string literal.
+func escapeJSONString(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return s
+	}
+
+	encoded := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	return string(encoded[1 : len(encoded)-1])
+}
+
+// decodeJSON decodes data into v, keeping numbers as [json.Number] so that
+// large integers such as IDs are not rounded.
+func decodeJSON(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after JSON value")
+	}
+	return nil
+}
+
+// stringifyValue returns strings as they are, and other values as JSON.
+func stringifyValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(encoded)
+}
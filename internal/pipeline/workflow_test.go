@@ -0,0 +1,155 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+func Test_workflow_run(t *testing.T) {
+	wf, err := newWorkflow([]CrawlStepConfig{
+		{
+			Name:    "login",
+			Method:  "POST",
+			URL:     "https://foo.com/login",
+			Body:    `{"user":"crawlert"}`,
+			Extract: map[string]string{"TOKEN": ".token"},
+		},
+		{
+			Name:    "list",
+			Method:  "GET",
+			URL:     "https://foo.com/items",
+			Header:  map[string]string{"Authorization": "Bearer $TOKEN"},
+			Extract: map[string]string{"IDS": "[ .[].id ]"},
+		},
+		{
+			Name:    "details",
+			Method:  "GET",
+			URL:     "https://foo.com/items/${ITEM}",
+			Header:  map[string]string{"Authorization": "Bearer $TOKEN"},
+			ForEach: "IDS",
+			Extract: map[string]string{"NAMES": "[ .[].name ]"},
+		},
+	}, false)
+	require.NoError(t, err)
+
+	var gotRequests []string
+	crawlPage := func(_ context.Context, req domain.CrawlRequest) (domain.CrawlResponse, error) {
+		gotRequests = append(gotRequests,
+			fmt.Sprintf("%s %s %s %s", req.Method, req.URL, req.Header.Get("Authorization"), req.Body))
+
+		switch req.URL {
+		case "https://foo.com/login":
+			return domain.CrawlResponse{Body: []byte(`{"token":"t0k"}`)}, nil
+		case "https://foo.com/items":
+			return domain.CrawlResponse{Body: []byte(`[{"id":1},{"id":2}]`)}, nil
+		case "https://foo.com/items/1":
+			return domain.CrawlResponse{Body: []byte(`{"id":1,"name":"one"}`)}, nil
+		case "https://foo.com/items/2":
+			return domain.CrawlResponse{StatusCode: 200, Body: []byte(`{"id":2,"name":"two"}`)}, nil
+		}
+		return domain.CrawlResponse{}, fmt.Errorf("unexpected url %s", req.URL)
+	}
+
+	resp, err := wf.run(context.Background(), crawlPage)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`POST https://foo.com/login  {"user":"crawlert"}`,
+		"GET https://foo.com/items Bearer t0k ",
+		"GET https://foo.com/items/1 Bearer t0k ",
+		"GET https://foo.com/items/2 Bearer t0k ",
+	}, gotRequests)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.JSONEq(t, `{
+		"login": {"token": "t0k"},
+		"list": [{"id": 1}, {"id": 2}],
+		"details": [{"id": 1, "name": "one"}, {"id": 2, "name": "two"}]
+	}`, string(resp.Body))
+}
+
+func Test_workflow_run_stepFailed(t *testing.T) {
+	wf, err := newWorkflow([]CrawlStepConfig{
+		{Name: "login", Method: "POST", URL: "https://foo.com/login"},
+		{Name: "list", Method: "GET", URL: "https://foo.com/items"},
+	}, false)
+	require.NoError(t, err)
+
+	_, err = wf.run(context.Background(), func(_ context.Context, req domain.CrawlRequest) (domain.CrawlResponse, error) {
+		if req.URL == "https://foo.com/login" {
+			return domain.CrawlResponse{}, &domain.HTTPStatusError{StatusCode: 401, Status: "401 Unauthorized"}
+		}
+		return domain.CrawlResponse{Body: []byte(`[]`)}, nil
+	})
+
+	var statusErr *domain.HTTPStatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, 401, statusErr.StatusCode)
+	assert.ErrorContains(t, err, "crawling step login")
+}
+
+func Test_workflow_run_escaping(t *testing.T) {
+	wf, err := newWorkflow([]CrawlStepConfig{
+		{
+			Name:    "search",
+			Method:  "GET",
+			URL:     "https://foo.com/search",
+			Extract: map[string]string{"QUERY": ".query", "IDS": "[ .ids[] ]", "NEXT": ".next"},
+		},
+		{
+			Name:    "details",
+			Method:  "POST",
+			URL:     "https://foo.com/items/$ITEM?q=$QUERY",
+			Body:    `{"id": $ITEM, "ids": $IDS, "query": "$QUERY"}`,
+			ForEach: "IDS",
+		},
+		{
+			Name:   "next",
+			Method: "GET",
+			URL:    "$NEXT",
+		},
+		{
+			Name:   "report",
+			Method: "POST",
+			URL:    "https://foo.com/report",
+			Header: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			Body:   "q=$QUERY",
+		},
+	}, true)
+	require.NoError(t, err)
+
+	var (
+		gotRequests         []string
+		gotAllowErrorStatus []bool
+	)
+	crawlPage := func(_ context.Context, req domain.CrawlRequest) (domain.CrawlResponse, error) {
+		gotRequests = append(gotRequests, fmt.Sprintf("%s %s", req.URL, req.Body))
+		gotAllowErrorStatus = append(gotAllowErrorStatus, req.AllowErrorStatus)
+
+		if req.URL == "https://foo.com/search" {
+			return domain.CrawlResponse{Body: []byte(
+				`{"query":"a&b \"c\"/d","ids":[9007199254740993],"next":"https://foo.com/search?page=2&q=a%20b"}`,
+			)}, nil
+		}
+		return domain.CrawlResponse{StatusCode: 404, Body: []byte(`{}`)}, nil
+	}
+
+	resp, err := wf.run(context.Background(), crawlPage)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"https://foo.com/search ",
+		`https://foo.com/items/9007199254740993?q=a%26b%20%22c%22%2Fd ` +
+			`{"id": 9007199254740993, "ids": [9007199254740993], "query": "a&b \"c\"/d"}`,
+		"https://foo.com/search?page=2&q=a%20b ",
+		"https://foo.com/report q=a%26b%20%22c%22%2Fd",
+	}, gotRequests)
+	assert.Equal(t, []bool{false, false, false, true}, gotAllowErrorStatus)
+	assert.Equal(t, 404, resp.StatusCode)
+	assert.Contains(t, string(resp.Body), `"ids":[9007199254740993]`)
+}
@@ -14,28 +14,47 @@ import (
 type Applier struct {
 	checkQuery      *gojq.Code
 	variableQueries map[string]*gojq.Code
+	itemsQuery      *gojq.Code
+	keyQuery        *gojq.Code
 }
 
-func NewApplier(checkQuery string, variableQueries map[string]string) (*Applier, error) {
-	check, err := compileJQQuery(checkQuery)
-	if err != nil {
-		return nil, fmt.Errorf("compiling check query: %w", err)
+func NewApplier(cfg ApplierConfig) (*Applier, error) {
+	var applier Applier
+
+	if cfg.Check != "" {
+		check, err := compileJQQuery(cfg.Check)
+		if err != nil {
+			return nil, fmt.Errorf("compiling check query: %w", err)
+		}
+		applier.checkQuery = check
 	}
 
-	variables := make(map[string]*gojq.Code, len(variableQueries))
-	for key, query := range variableQueries {
+	applier.variableQueries = make(map[string]*gojq.Code, len(cfg.Variables))
+	for key, query := range cfg.Variables {
 		q, err := compileJQQuery(query)
 		if err != nil {
 			return nil, fmt.Errorf("compiling query of variable %s: %w", key, err)
 		}
 
-		variables[key] = q
+		applier.variableQueries[key] = q
 	}
 
-	return &Applier{
-		checkQuery:      check,
-		variableQueries: variables,
-	}, nil
+	if cfg.Items != "" {
+		items, err := compileJQQuery(cfg.Items)
+		if err != nil {
+			return nil, fmt.Errorf("compiling items query: %w", err)
+		}
+
+		key, err := compileJQQuery(cfg.Key)
+		if err != nil {
+			return nil, fmt.Errorf("compiling key query: %w", err)
+		}
+
+		applier.itemsQuery = items
+		applier.keyQuery = key
+	}
+
+	return &applier, nil
 }
 
 func (e *Applier) ApplyQuery(jsonBytes []byte) (domain.QueryResult, error) {
@@ -44,9 +63,13 @@ func (e *Applier) ApplyQuery(jsonBytes []byte) (domain.QueryResult, error) {
 		return domain.QueryResult{}, fmt.Errorf("unmarshaling into json: %w", err)
 	}
 
-	checkResult, err := queryFirstItem(e.checkQuery, target)
-	if err != nil {
-		return domain.QueryResult{}, fmt.Errorf("applying check query: %w", err)
+	matched := true
+	if e.checkQuery != nil {
+		checkResult, err := queryFirstItem(e.checkQuery, target)
+		if err != nil {
+			return domain.QueryResult{}, fmt.Errorf("applying check query: %w", err)
+		}
+		matched = isTruthyValue(checkResult)
 	}
 
 	variables := make(map[string]string, len(e.variableQueries))
@@ -58,35 +81,97 @@ func (e *Applier) ApplyQuery(jsonBytes []byte) (domain.QueryResult, error) {
 		variables[key] = result
 	}
 
+	var items []domain.QueryItem
+	if e.itemsQuery != nil {
+		collected, err := e.collectItems(target)
+		if err != nil {
+			return domain.QueryResult{}, fmt.Errorf("collecting items: %w", err)
+		}
+		items = collected
+	}
+
 	return domain.QueryResult{
-		Matched:   isTruthyValue(checkResult),
+		Matched:   matched,
 		Variables: variables,
+		Items:     items,
 	}, nil
 }
 
-func queryFirstItem(query *gojq.Code, target any) (string, error) {
-	var result string
-	iter := query.Run(target)
-	for {
-		v, ok := iter.Next()
-		if !ok {
-			break
+func (e *Applier) collectItems(target any) ([]domain.QueryItem, error) {
+	result, ok, err := queryFirstValue(e.itemsQuery, target)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("applying items query: %w", err)
+	case !ok:
+		return nil, nil
+	}
+
+	values, ok := result.([]any)
+	if !ok {
+		return nil, fmt.Errorf("items query should produce an array, but got %T", result)
+	}
+
+	items := make([]domain.QueryItem, 0, len(values))
+	for i, v := range values {
+		key, ok, err := queryFirstValue(e.keyQuery, v)
+		switch {
+		case err != nil:
+			return nil, fmt.Errorf("applying key query to item %d: %w", i, err)
+		case !ok || key == nil:
+			return nil, fmt.Errorf("key query produced nothing for item %d", i)
 		}
 
-		if err, ok := v.(error); ok {
-			return "", fmt.Errorf("iterating result: %w", err)
+		keyString, ok := key.(string)
+		if !ok {
+			encoded, err := json.Marshal(key)
+			if err != nil {
+				return nil, fmt.Errorf("json marshaling key of item %d: %w", i, err)
+			}
+			keyString = string(encoded)
 		}
 
 		encoded, err := json.Marshal(v)
 		if err != nil {
-			return "", fmt.Errorf("json marshaling query result: %w", err)
+			return nil, fmt.Errorf("json marshaling item %d: %w", i, err)
 		}
 
-		result = string(encoded)
-		break
+		items = append(items, domain.QueryItem{
+			Key:   keyString,
+			Value: string(encoded),
+		})
+	}
+
+	return items, nil
+}
+
+func queryFirstItem(query *gojq.Code, target any) (string, error) {
+	v, ok, err := queryFirstValue(query, target)
+	if err != nil || !ok {
+		return "", err
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("json marshaling query result: %w", err)
+	}
+
+	return string(encoded), nil
+}
+
+// queryFirstValue returns the first result of query. It reports false if the
+// query produced nothing.
+func queryFirstValue(query *gojq.Code, target any) (any, bool, error) {
+	iter := query.Run(target)
+	v, ok := iter.Next()
+	if !ok {
+		return nil, false, nil
+	}
+
+	if err, ok := v.(error); ok {
+		return nil, false, fmt.Errorf("iterating result: %w", err)
 	}
 
-	return result, nil
+	return v, true, nil
 }
 
 func compileJQQuery(s string) (*gojq.Code, error) {
@@ -59,8 +59,7 @@ var rawJSONs = [...]string{
 
 func TestExecutor_ApplyQuery(t *testing.T) {
 	type inits struct {
-		checkQuery      string
-		variableQueries map[string]string
+		cfg ApplierConfig
 	}
 	type args struct {
 		jsonBytes []byte
@@ -75,7 +74,9 @@ func TestExecutor_ApplyQuery(t *testing.T) {
 		{
 			name: "select_length",
 			inits: inits{
-				checkQuery: `[ .[] | select(.name == "apple") ] | length`,
+				cfg: ApplierConfig{
+					Check: `[ .[] | select(.name == "apple") ] | length`,
+				},
 			},
 			args: args{
 				jsonBytes: []byte(rawJSONs[0]),
@@ -88,7 +89,9 @@ func TestExecutor_ApplyQuery(t *testing.T) {
 		{
 			name: "not_found_by_select",
 			inits: inits{
-				checkQuery: `[ .[] | select(.foo == "bar") ] | length`,
+				cfg: ApplierConfig{
+					Check: `[ .[] | select(.foo == "bar") ] | length`,
+				},
 			},
 			args: args{
 				jsonBytes: []byte(rawJSONs[0]),
@@ -101,10 +104,12 @@ func TestExecutor_ApplyQuery(t *testing.T) {
 		{
 			name: "variables_are_set",
 			inits: inits{
-				checkQuery: `.people[] | select( .friends | length >= 2 ) | length`,
-				variableQueries: map[string]string{
-					"NAMES":       `[ .people[] | .name ]`,
-					"BAD_FREINDS": `[ .people[] | { "name": .name, "badFriends": [ .friends[] | select( .relationship == "bad" or .relationship == "poor" ) ] } ]`,
+				cfg: ApplierConfig{
+					Check: `.people[] | select( .friends | length >= 2 ) | length`,
+					Variables: map[string]string{
+						"NAMES":       `[ .people[] | .name ]`,
+						"BAD_FREINDS": `[ .people[] | { "name": .name, "badFriends": [ .friends[] | select( .relationship == "bad" or .relationship == "poor" ) ] } ]`,
+					},
 				},
 			},
 			args: args{
@@ -118,11 +123,64 @@ func TestExecutor_ApplyQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "items_are_collected",
+			inits: inits{
+				cfg: ApplierConfig{
+					Items: `[ .[] | select(.color == "green") ]`,
+					Key:   `.name`,
+				},
+			},
+			args: args{
+				jsonBytes: []byte(rawJSONs[0]),
+			},
+			want: domain.QueryResult{
+				Matched:   true,
+				Variables: map[string]string{},
+				Items: []domain.QueryItem{
+					{Key: "apple", Value: `{"color":"green","name":"apple","price":1.2}`},
+					{Key: "kiwi", Value: `{"color":"green","name":"kiwi","price":1.25}`},
+				},
+			},
+		},
+		{
+			name: "non_string_keys_are_encoded",
+			inits: inits{
+				cfg: ApplierConfig{
+					Items: `.people`,
+					Key:   `.age`,
+				},
+			},
+			args: args{
+				jsonBytes: []byte(rawJSONs[1]),
+			},
+			want: domain.QueryResult{
+				Matched:   true,
+				Variables: map[string]string{},
+				Items: []domain.QueryItem{
+					{Key: "20", Value: `{"age":20,"friends":[{"name":"friend-one","relationship":"good"}],"name":"Alice"}`},
+					{Key: "24", Value: `{"age":24,"friends":[{"name":"friend-two","relationship":"poor"},{"name":"friend-three","relationship":"bad"}],"name":"Bob"}`},
+				},
+			},
+		},
+		{
+			name: "items_should_be_array",
+			inits: inits{
+				cfg: ApplierConfig{
+					Items: `.people[0]`,
+					Key:   `.name`,
+				},
+			},
+			args: args{
+				jsonBytes: []byte(rawJSONs[1]),
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			e, err := NewApplier(tt.inits.checkQuery, tt.inits.variableQueries)
+			e, err := NewApplier(tt.inits.cfg)
 			require.NoError(t, err)
 
 			resp, err := e.ApplyQuery(tt.args.jsonBytes)
@@ -0,0 +1,29 @@
+package query
+
+type ApplierConfig struct {
+	// Check is a jq query whose result decides whether the query matched. The
+	// query always matches if empty.
+	Check string
+
+	// Variables are jq queries of which results are exposed as variables.
+	Variables map[string]string
+
+	// Items is a jq query which produces an array of items. Items are not
+	// collected if empty.
+	Items string
+
+	// Key is a jq query applied to each item which produces the identity of
+	// the item.
+	Key string
+}
+
+type RegexApplierConfig struct {
+	// Check is a regular expression which decides whether the query matched.
+	// Named capture groups of the first match are exposed as variables.
+	Check string
+
+	// Variables are regular expressions of which first matches are exposed as
+	// variables. The first capture group is used if any, or the whole match
+	// otherwise.
+	Variables map[string]string
+}
@@ -0,0 +1,22 @@
+package query
+
+import "github.com/itchyny/gojq"
+
+// Expression is a single jq query evaluated against JSON documents.
+type Expression struct {
+	code *gojq.Code
+}
+
+func NewExpression(query string) (*Expression, error) {
+	code, err := compileJQQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return &Expression{code: code}, nil
+}
+
+// EvaluateValue returns the first result of the expression applied to a
+// decoded JSON value. It reports false if the expression produced nothing.
+func (e *Expression) EvaluateValue(target any) (any, bool, error) {
+	return queryFirstValue(e.code, target)
+}
@@ -0,0 +1,77 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+// RegexApplier applies regular expressions to text, such as plain text pages
+// and logs.
+type RegexApplier struct {
+	checkRegex      *regexp.Regexp
+	variableRegexes map[string]*regexp.Regexp
+}
+
+func NewRegexApplier(cfg RegexApplierConfig) (*RegexApplier, error) {
+	check, err := regexp.Compile(cfg.Check)
+	if err != nil {
+		return nil, fmt.Errorf("compiling check regex: %w", err)
+	}
+
+	variableRegexes := make(map[string]*regexp.Regexp, len(cfg.Variables))
+	for key, expr := range cfg.Variables {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("compiling regex of variable %s: %w", key, err)
+		}
+		variableRegexes[key] = re
+	}
+
+	return &RegexApplier{
+		checkRegex:      check,
+		variableRegexes: variableRegexes,
+	}, nil
+}
+
+func (a *RegexApplier) ApplyQuery(text []byte) (domain.QueryResult, error) {
+	variables := make(map[string]string, len(a.variableRegexes))
+	for key, re := range a.variableRegexes {
+		variables[key] = firstSubmatch(re, text)
+	}
+
+	match := a.checkRegex.FindSubmatch(text)
+	if match == nil {
+		return domain.QueryResult{
+			Matched:   false,
+			Variables: variables,
+		}, nil
+	}
+
+	for i, name := range a.checkRegex.SubexpNames() {
+		if name != "" {
+			variables[name] = string(match[i])
+		}
+	}
+
+	return domain.QueryResult{
+		Matched:   true,
+		Variables: variables,
+	}, nil
+}
+
+// firstSubmatch returns the first capture group of the first match of re, or
+// the whole match if re has no capture group. Empty string is returned if re
+// does not match.
+func firstSubmatch(re *regexp.Regexp, text []byte) string {
+	match := re.FindSubmatch(text)
+	switch {
+	case match == nil:
+		return ""
+	case len(match) > 1:
+		return string(match[1])
+	default:
+		return string(match[0])
+	}
+}
@@ -0,0 +1,96 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+const rawLog = `2024-10-01T10:00:00Z INFO server started version=1.4.2
+2024-10-01T10:05:12Z ERROR disk usage 97% on /dev/sda1
+2024-10-01T10:06:40Z ERROR disk usage 98% on /dev/sda1
+`
+
+func TestRegexApplier_ApplyQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     RegexApplierConfig
+		text    string
+		want    domain.QueryResult
+		wantErr bool
+	}{
+		{
+			name: "named_groups_become_variables",
+			cfg: RegexApplierConfig{
+				Check: `ERROR disk usage (?P<USAGE>\d+)% on (?P<DEVICE>\S+)`,
+			},
+			text: rawLog,
+			want: domain.QueryResult{
+				Matched: true,
+				Variables: map[string]string{
+					"USAGE":  "97",
+					"DEVICE": "/dev/sda1",
+				},
+			},
+		},
+		{
+			name: "not_matched",
+			cfg: RegexApplierConfig{
+				Check: `(?m)^\S+ FATAL (?P<REASON>.+)$`,
+				Variables: map[string]string{
+					"VERSION": `version=(\S+)`,
+				},
+			},
+			text: rawLog,
+			want: domain.QueryResult{
+				Matched: false,
+				Variables: map[string]string{
+					"VERSION": "1.4.2",
+				},
+			},
+		},
+		{
+			name: "variables_without_group_use_whole_match",
+			cfg: RegexApplierConfig{
+				Check: `ERROR`,
+				Variables: map[string]string{
+					"FIRST_ERROR": `(?m)^.+ERROR.+$`,
+					"MISSING":     `WARN (\w+)`,
+				},
+			},
+			text: rawLog,
+			want: domain.QueryResult{
+				Matched: true,
+				Variables: map[string]string{
+					"FIRST_ERROR": "2024-10-01T10:05:12Z ERROR disk usage 97% on /dev/sda1",
+					"MISSING":     "",
+				},
+			},
+		},
+		{
+			name: "invalid_check",
+			cfg: RegexApplierConfig{
+				Check: `(unclosed`,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applier, err := NewRegexApplier(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			got, err := applier.ApplyQuery([]byte(tt.text))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// IsRetryableStatus reports whether a request failed with the status code
+// may succeed later.
+func IsRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// HTTPError marks err of an HTTP request as retryable if it is caused by
+// network failure or retryable status. Delay requested by Retry-After header
+// is honored.
+func HTTPError(err error, resp *http.Response) error {
+	if resp == nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		return Retryable(err, 0)
+	}
+
+	if !IsRetryableStatus(resp.StatusCode) {
+		return err
+	}
+
+	after, _ := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	return Retryable(err, after)
+}
+
+// ParseRetryAfter parses value of Retry-After header, which is either seconds
+// or HTTP date.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name:   "seconds",
+			value:  "3",
+			want:   3 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "fractional_seconds",
+			value:  "0.5",
+			want:   500 * time.Millisecond,
+			wantOK: true,
+		},
+		{
+			name:   "http_date",
+			value:  "Tue, 01 Oct 2024 12:00:10 GMT",
+			want:   10 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "past_http_date",
+			value:  "Tue, 01 Oct 2024 11:00:00 GMT",
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name:   "empty",
+			value:  "",
+			wantOK: false,
+		},
+		{
+			name:   "invalid",
+			value:  "soon",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseRetryAfter(tt.value, now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Policy is the budget of retries. Zero value of Policy does not retry.
+type Policy struct {
+	// MaxAttempts is the maximum number of attempts including the first one.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. The delay doubles
+	// on each retry up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// MaxElapsed is the maximum time from the first attempt to the start of
+	// the last retry. Retries are given up if the next delay would exceed it.
+	// No limit if zero.
+	MaxElapsed time.Duration
+}
+
+// Error is a retryable error.
+type Error struct {
+	Err error
+
+	// After is the delay requested by the server before the next attempt.
+	// Exponential backoff is used if zero.
+	After time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Retryable marks err as retryable after the given delay.
+func Retryable(err error, after time.Duration) error {
+	return &Error{Err: err, After: after}
+}
+
+// Do calls fn until it succeeds, it returns an error not marked by
+// [Retryable], or attempts or elapsed time of policy are exhausted. Retries are
+// also given up if the server requests a delay longer than MaxBackoff.
+func Do(ctx context.Context, policy Policy, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		var retryErr *Error
+		if !errors.As(err, &retryErr) {
+			return err
+		}
+		if attempt >= policy.MaxAttempts {
+			if attempt > 1 {
+				return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+			}
+			return err
+		}
+
+		delay := backoff
+		if retryErr.After > delay {
+			delay = retryErr.After
+		}
+		if policy.MaxBackoff > 0 && retryErr.After > policy.MaxBackoff {
+			return fmt.Errorf("giving up as requested delay %v exceeds max backoff: %w", retryErr.After, err)
+		}
+		if policy.MaxElapsed > 0 && time.Since(start)+delay > policy.MaxElapsed {
+			return fmt.Errorf("giving up after %d attempts as max elapsed time is exceeded: %w", attempt, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("waiting for retry: %w", errors.Join(ctx.Err(), err))
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDo(t *testing.T) {
+	errTemporary := errors.New("temporary")
+	errPermanent := errors.New("permanent")
+
+	policy := Policy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name         string
+		policy       Policy
+		errs         []error
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:         "success_at_first",
+			policy:       policy,
+			errs:         []error{nil},
+			wantAttempts: 1,
+		},
+		{
+			name:         "success_after_retries",
+			policy:       policy,
+			errs:         []error{Retryable(errTemporary, 0), Retryable(errTemporary, time.Millisecond), nil},
+			wantAttempts: 3,
+		},
+		{
+			name:         "permanent_error",
+			policy:       policy,
+			errs:         []error{Retryable(errTemporary, 0), errPermanent},
+			wantAttempts: 2,
+			wantErr:      errPermanent,
+		},
+		{
+			name:         "attempts_exhausted",
+			policy:       policy,
+			errs:         []error{Retryable(errTemporary, 0), Retryable(errTemporary, 0), Retryable(errTemporary, 0)},
+			wantAttempts: 3,
+			wantErr:      errTemporary,
+		},
+		{
+			name:         "requested_delay_exceeds_max_backoff",
+			policy:       policy,
+			errs:         []error{Retryable(errTemporary, time.Hour)},
+			wantAttempts: 1,
+			wantErr:      errTemporary,
+		},
+		{
+			name: "max_elapsed_exceeded",
+			policy: Policy{
+				MaxAttempts:    5,
+				InitialBackoff: 2 * time.Millisecond,
+				MaxBackoff:     time.Second,
+				MaxElapsed:     5 * time.Millisecond,
+			},
+			errs:         []error{Retryable(errTemporary, 0), Retryable(errTemporary, 0), Retryable(errTemporary, 0)},
+			wantAttempts: 2,
+			wantErr:      errTemporary,
+		},
+		{
+			name:         "zero_policy",
+			errs:         []error{Retryable(errTemporary, 0)},
+			wantAttempts: 1,
+			wantErr:      errTemporary,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := Do(context.Background(), tt.policy, func(ctx context.Context) error {
+				err := tt.errs[attempts]
+				attempts++
+				return err
+			})
+
+			assert.Equal(t, tt.wantAttempts, attempts)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDo_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	policy := Policy{MaxAttempts: 3, InitialBackoff: time.Hour}
+	err := Do(ctx, policy, func(ctx context.Context) error {
+		return Retryable(errors.New("temporary"), 0)
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
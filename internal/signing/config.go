@@ -0,0 +1,109 @@
+package signing
+
+import "fmt"
+
+type SignerConfig struct {
+	Type  Type
+	HMAC  HMACConfig
+	SigV4 SigV4Config
+}
+
+// Type is the signature scheme of crawl requests. Empty type means requests
+// are not signed.
+type Type string
+
+const (
+	TypeHMAC  Type = "hmac"
+	TypeSigV4 Type = "sigv4"
+)
+
+func (t Type) Validate() error {
+	switch t {
+	case "", TypeHMAC, TypeSigV4:
+		return nil
+	default:
+		return fmt.Errorf("unknown signing type '%s'", t)
+	}
+}
+
+// HMACConfig signs a templated string with HMAC-SHA256. Templates can
+// reference the following variables.
+//   - $METHOD: HTTP method
+//   - $PATH: escaped URL path
+//   - $QUERY: raw query string, including the timestamp parameter but not the
+//     signature parameter
+//   - $URI: $PATH followed by ?$QUERY if the query is not empty
+//   - $HOST: host of the URL
+//   - $BODY: request body
+//   - $BODY_SHA256: hex encoded SHA-256 of the request body
+//   - $TIMESTAMP: time of signing formatted by TimestampFormat
+//   - $SIGNATURE: the signature, only in Headers
+type HMACConfig struct {
+	Secret string
+
+	// StringToSign is the template of the signed string.
+	StringToSign string
+
+	Encoding        Encoding
+	TimestampFormat TimestampFormat
+
+	// TimestampParam is the query parameter of the timestamp, appended before
+	// signing if set.
+	TimestampParam string
+
+	// SignatureHeader is the header of the signature. SignatureParam is the
+	// query parameter of the signature, appended after the other parameters.
+	// Only one of them is set.
+	SignatureHeader string
+	SignatureParam  string
+
+	// Headers are templates of additional headers, such as the timestamp and
+	// the API key.
+	Headers map[string]string
+}
+
+// Encoding is the encoding of HMAC signatures.
+type Encoding string
+
+const (
+	EncodingHex    Encoding = "hex"
+	EncodingBase64 Encoding = "base64"
+)
+
+func (e Encoding) Validate() error {
+	switch e {
+	case "", EncodingHex, EncodingBase64:
+		return nil
+	default:
+		return fmt.Errorf("unknown encoding '%s'", e)
+	}
+}
+
+// TimestampFormat is the format of $TIMESTAMP.
+type TimestampFormat string
+
+const (
+	TimestampUnix         TimestampFormat = "unix"
+	TimestampUnixMilli    TimestampFormat = "unix-ms"
+	TimestampRFC3339      TimestampFormat = "rfc3339"
+	TimestampRFC3339Milli TimestampFormat = "rfc3339-ms"
+)
+
+func (f TimestampFormat) Validate() error {
+	switch f {
+	case "", TimestampUnix, TimestampUnixMilli, TimestampRFC3339, TimestampRFC3339Milli:
+		return nil
+	default:
+		return fmt.Errorf("unknown timestamp format '%s'", f)
+	}
+}
+
+// SigV4Config is AWS Signature Version 4. Credentials are read from the
+// standard AWS environment variables if access key ID is empty.
+type SigV4Config struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+	Service         string
+}
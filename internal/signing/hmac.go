@@ -0,0 +1,126 @@
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/isutare412/crawlert/internal/template"
+)
+
+const (
+	variableMethod     = "METHOD"
+	variablePath       = "PATH"
+	variableQuery      = "QUERY"
+	variableURI        = "URI"
+	variableHost       = "HOST"
+	variableBody       = "BODY"
+	variableBodySHA256 = "BODY_SHA256"
+	variableTimestamp  = "TIMESTAMP"
+	variableSignature  = "SIGNATURE"
+)
+
+// HMACSigner signs requests with HMAC-SHA256 over a templated string.
+type HMACSigner struct {
+	secret          []byte
+	stringToSign    string
+	encoding        Encoding
+	timestampFormat TimestampFormat
+	timestampParam  string
+	signatureHeader string
+	signatureParam  string
+	headers         map[string]string
+
+	now func() time.Time
+}
+
+func NewHMACSigner(cfg HMACConfig) *HMACSigner {
+	return &HMACSigner{
+		secret:          []byte(cfg.Secret),
+		stringToSign:    cfg.StringToSign,
+		encoding:        cfg.Encoding,
+		timestampFormat: cfg.TimestampFormat,
+		timestampParam:  cfg.TimestampParam,
+		signatureHeader: cfg.SignatureHeader,
+		signatureParam:  cfg.SignatureParam,
+		headers:         cfg.Headers,
+		now:             time.Now,
+	}
+}
+
+func (s *HMACSigner) SignRequest(req *http.Request, body []byte) error {
+	timestamp := s.formatTimestamp(s.now())
+	if s.timestampParam != "" {
+		appendQueryParam(req, s.timestampParam, timestamp)
+	}
+
+	bodyHash := sha256.Sum256(body)
+	uri := req.URL.EscapedPath()
+	if req.URL.RawQuery != "" {
+		uri += "?" + req.URL.RawQuery
+	}
+
+	variables := map[string]string{
+		variableMethod:     req.Method,
+		variablePath:       req.URL.EscapedPath(),
+		variableQuery:      req.URL.RawQuery,
+		variableURI:        uri,
+		variableHost:       req.URL.Host,
+		variableBody:       string(body),
+		variableBodySHA256: hex.EncodeToString(bodyHash[:]),
+		variableTimestamp:  timestamp,
+	}
+
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(template.Render(s.stringToSign, variables)))
+	signature := s.encode(mac.Sum(nil))
+	variables[variableSignature] = signature
+
+	if s.signatureHeader != "" {
+		req.Header.Set(s.signatureHeader, signature)
+	}
+	if s.signatureParam != "" {
+		appendQueryParam(req, s.signatureParam, signature)
+	}
+	for k, v := range s.headers {
+		req.Header.Set(k, template.Render(v, variables))
+	}
+
+	return nil
+}
+
+func (s *HMACSigner) encode(sum []byte) string {
+	if s.encoding == EncodingBase64 {
+		return base64.StdEncoding.EncodeToString(sum)
+	}
+	return hex.EncodeToString(sum)
+}
+
+func (s *HMACSigner) formatTimestamp(t time.Time) string {
+	switch s.timestampFormat {
+	case TimestampUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case TimestampRFC3339:
+		return t.UTC().Format(time.RFC3339)
+	case TimestampRFC3339Milli:
+		return t.UTC().Format("2006-01-02T15:04:05.000Z07:00")
+	default:
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+}
+
+// appendQueryParam appends a query parameter to the URL of req, keeping the
+// order of existing parameters.
+func appendQueryParam(req *http.Request, name, value string) {
+	param := url.QueryEscape(name) + "=" + url.QueryEscape(value)
+	if req.URL.RawQuery == "" {
+		req.URL.RawQuery = param
+	} else {
+		req.URL.RawQuery += "&" + param
+	}
+}
@@ -0,0 +1,92 @@
+package signing
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHMACSigner_SignRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        HMACConfig
+		method     string
+		url        string
+		body       string
+		wantURL    string
+		wantHeader map[string]string
+	}{
+		{
+			name: "signature_header",
+			cfg: HMACConfig{
+				Secret:          "s3cret",
+				StringToSign:    "$TIMESTAMP$METHOD$URI$BODY",
+				Encoding:        EncodingBase64,
+				SignatureHeader: "X-Access-Sign",
+				Headers: map[string]string{
+					"X-Access-Timestamp": "$TIMESTAMP",
+					"X-Access-Key":       "key",
+				},
+			},
+			method:  http.MethodGet,
+			url:     "https://api.foo.com/api/v3/orders?symbol=BTCUSD",
+			wantURL: "https://api.foo.com/api/v3/orders?symbol=BTCUSD",
+			wantHeader: map[string]string{
+				"X-Access-Sign":      "ti1nT8Ug/8RXhQkxVesZX99WN/JbWOBxWzwK2nCGZhY=",
+				"X-Access-Timestamp": "1700000000",
+				"X-Access-Key":       "key",
+			},
+		},
+		{
+			name: "signature_with_body",
+			cfg: HMACConfig{
+				Secret:          "s3cret",
+				StringToSign:    "${TIMESTAMP}${METHOD}${PATH}${BODY}",
+				Encoding:        EncodingBase64,
+				SignatureHeader: "X-Access-Sign",
+			},
+			method:  http.MethodPost,
+			url:     "https://api.foo.com/api/v3/orders",
+			body:    `{"qty":1}`,
+			wantURL: "https://api.foo.com/api/v3/orders",
+			wantHeader: map[string]string{
+				"X-Access-Sign": "LlYgBFaO2GPcnUHqev8MxMJJ7YgHwkjPwNFyvPHZm7k=",
+			},
+		},
+		{
+			name: "signature_param",
+			cfg: HMACConfig{
+				Secret:          "s3cret",
+				StringToSign:    "$QUERY",
+				TimestampFormat: TimestampUnixMilli,
+				TimestampParam:  "timestamp",
+				SignatureParam:  "signature",
+			},
+			method: http.MethodGet,
+			url:    "https://api.foo.com/api/v3/orders?symbol=BTCUSD",
+			wantURL: "https://api.foo.com/api/v3/orders?symbol=BTCUSD&timestamp=1700000000000" +
+				"&signature=31e1bd101104b01f6fd7442b0e9a44d23ba231e3f1e2885dbedaced19b5d82d8",
+			wantHeader: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := NewHMACSigner(tt.cfg)
+			signer.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+			req, err := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			require.NoError(t, signer.SignRequest(req, []byte(tt.body)))
+			assert.Equal(t, tt.wantURL, req.URL.String())
+			for k, v := range tt.wantHeader {
+				assert.Equal(t, v, req.Header.Get(k), k)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package signing
+
+import (
+	"fmt"
+
+	"github.com/isutare412/crawlert/internal/core/port"
+)
+
+// NewSigner returns the signer of cfg, or nil if cfg has no signing type.
+func NewSigner(cfg SignerConfig) (port.RequestSigner, error) {
+	switch cfg.Type {
+	case "":
+		return nil, nil
+	case TypeHMAC:
+		return NewHMACSigner(cfg.HMAC), nil
+	case TypeSigV4:
+		signer, err := NewSigV4Signer(cfg.SigV4)
+		if err != nil {
+			return nil, fmt.Errorf("creating sigv4 signer: %w", err)
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("unknown signing type '%s'", cfg.Type)
+	}
+}
@@ -0,0 +1,122 @@
+package signing
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test cases are taken from the AWS Signature Version 4 test suite.
+func TestSigV4Signer_SignRequest(t *testing.T) {
+	tests := []struct {
+		name              string
+		method            string
+		url               string
+		body              string
+		singleEncodePath  bool
+		wantAuthorization string
+	}{
+		{
+			name:   "get_vanilla",
+			method: http.MethodGet,
+			url:    "https://example.amazonaws.com/",
+			wantAuthorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:   "get_vanilla_query_order_key_case",
+			method: http.MethodGet,
+			url:    "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			wantAuthorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		{
+			// Paths of the test suite are encoded once, as S3 expects.
+			name:             "get_utf8",
+			method:           http.MethodGet,
+			url:              "https://example.amazonaws.com/ሴ",
+			singleEncodePath: true,
+			wantAuthorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=8318018e0b0f223aa2bbf98705b62bb787dc9c0e678f255a891fd03141be5d85",
+		},
+		{
+			name:             "get_space",
+			method:           http.MethodGet,
+			url:              "https://example.amazonaws.com/example%20space/",
+			singleEncodePath: true,
+			wantAuthorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=652487583200325589f1fba4c7e578f72c47cb61beeca81406b39ddec1366741",
+		},
+		{
+			name:   "post_vanilla",
+			method: http.MethodPost,
+			url:    "https://example.amazonaws.com/",
+			wantAuthorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := NewSigV4Signer(SigV4Config{
+				AccessKeyID:     "AKIDEXAMPLE",
+				SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+				Region:          "us-east-1",
+				Service:         "service",
+			})
+			require.NoError(t, err)
+			signer.now = func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) }
+			signer.doubleEncodePath = !tt.singleEncodePath
+
+			req, err := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			require.NoError(t, signer.SignRequest(req, []byte(tt.body)))
+			assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+			assert.Equal(t, tt.wantAuthorization, req.Header.Get("Authorization"))
+		})
+	}
+}
+
+func Test_canonicalURI(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		doubleEncode bool
+		want         string
+	}{
+		{
+			name: "empty_path",
+			url:  "https://example.amazonaws.com",
+			want: "/",
+		},
+		{
+			name: "single_encode",
+			url:  "https://example.amazonaws.com/documents%20and%20settings/a%2Fb=c",
+			want: "/documents%20and%20settings/a%2Fb%3Dc",
+		},
+		{
+			name:         "double_encode",
+			url:          "https://example.amazonaws.com/documents%20and%20settings/a%2Fb=c",
+			doubleEncode: true,
+			want:         "/documents%2520and%2520settings/a%252Fb%253Dc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, canonicalURI(req, tt.doubleEncode))
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package slack
+
+import "github.com/isutare412/crawlert/internal/retry"
+
+type MessageSenderConfig struct {
+	WebhookURL string
+	Retry      retry.Policy
+}
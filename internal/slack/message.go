@@ -0,0 +1,123 @@
+package slack
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxSectionTextLength is the maximum length of text in a section block.
+	// https://api.slack.com/reference/block-kit/blocks#section
+	maxSectionTextLength = 3000
+
+	// maxBlocks is the maximum number of blocks in a message.
+	// https://api.slack.com/reference/block-kit/blocks
+	maxBlocks = 50
+
+	// maxFallbackTextLength is the length of text used in notifications. Slack
+	// truncates text longer than 40,000 characters, but recommends to keep it
+	// under 4,000 characters.
+	// https://api.slack.com/methods/chat.postMessage#truncating
+	maxFallbackTextLength = 4000
+)
+
+var mrkdwnEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+)
+
+type webhookRequest struct {
+	Text   string  `json:"text"`
+	Blocks []block `json:"blocks"`
+}
+
+type block struct {
+	Type string     `json:"type"`
+	Text textObject `json:"text"`
+}
+
+type textObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// newWebhookRequests builds requests which carry msg as mrkdwn section blocks.
+// Multiple requests are built if msg needs more blocks than a message can
+// have.
+func newWebhookRequests(msg string) []webhookRequest {
+	chunks := splitMessage(escapeForMrkdwn(msg))
+
+	var reqs []webhookRequest
+	for len(chunks) > 0 {
+		n := min(len(chunks), maxBlocks)
+
+		blocks := make([]block, 0, n)
+		for _, c := range chunks[:n] {
+			blocks = append(blocks, block{
+				Type: "section",
+				Text: textObject{Type: "mrkdwn", Text: c},
+			})
+		}
+
+		reqs = append(reqs, webhookRequest{
+			Text:   truncate(chunks[0], maxFallbackTextLength),
+			Blocks: blocks,
+		})
+		chunks = chunks[n:]
+	}
+
+	return reqs
+}
+
+// escapeForMrkdwn escapes control characters of Slack mrkdwn.
+// https://api.slack.com/reference/surfaces/formatting#escaping
+func escapeForMrkdwn(s string) string {
+	return mrkdwnEscaper.Replace(s)
+}
+
+// splitMessage splits an escaped message into chunks that fit within a section
+// block, preferring to split at newline boundaries. Characters and escaped
+// entities are never split.
+func splitMessage(msg string) []string {
+	var chunks []string
+	for len(msg) > maxSectionTextLength {
+		splitAt := strings.LastIndexByte(msg[:maxSectionTextLength], '\n') + 1
+		if splitAt <= 0 {
+			splitAt = safeSplitIndex(msg, maxSectionTextLength)
+		}
+
+		chunks = append(chunks, msg[:splitAt])
+		msg = msg[splitAt:]
+	}
+
+	if len(msg) > 0 || len(chunks) == 0 {
+		chunks = append(chunks, msg)
+	}
+	return chunks
+}
+
+// safeSplitIndex returns the largest index not greater than n where s can be
+// split without breaking a UTF-8 character or an escaped entity.
+func safeSplitIndex(s string, n int) int {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	// Escaped entities are at most 5 bytes long, e.g. "&amp;".
+	if amp := strings.LastIndexByte(s[max(n-4, 0):n], '&'); amp >= 0 {
+		amp += max(n-4, 0)
+		if !strings.Contains(s[amp:n], ";") {
+			n = amp
+		}
+	}
+
+	return n
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:safeSplitIndex(s, n)]
+}
@@ -0,0 +1,86 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/retry"
+)
+
+type MessageSender struct {
+	httpClient *http.Client
+
+	webhookURL  string
+	retryPolicy retry.Policy
+}
+
+func NewMessageSender(cfg MessageSenderConfig) *MessageSender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 100
+
+	return &MessageSender{
+		httpClient:  &http.Client{Transport: transport},
+		webhookURL:  cfg.WebhookURL,
+		retryPolicy: cfg.Retry,
+	}
+}
+
+// Destination returns the webhook URL to which messages are sent.
+func (s *MessageSender) Destination() string {
+	return s.webhookURL
+}
+
+func (s *MessageSender) SendMessage(ctx context.Context, message domain.Message) error {
+	if len(message.Text) == 0 {
+		return nil
+	}
+
+	reqs := newWebhookRequests(message.Text)
+	for i, req := range reqs {
+		err := retry.Do(ctx, s.retryPolicy, func(ctx context.Context) error {
+			return s.sendRequest(ctx, req)
+		})
+		if err != nil {
+			return fmt.Errorf("sending request %d/%d: %w", i+1, len(reqs), err)
+		}
+	}
+
+	return nil
+}
+
+func (s *MessageSender) sendRequest(ctx context.Context, req webhookRequest) error {
+	reqBody, err := json.Marshal(&req)
+	if err != nil {
+		return fmt.Errorf("marshaling message body: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.webhookURL, bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("building http request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return retry.HTTPError(fmt.Errorf("doing http request: %w", err), nil)
+	}
+	defer httpResp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("unexpected response status code %s; body (%s)", httpResp.Status, string(bodyBytes))
+		return retry.HTTPError(err, httpResp)
+	}
+
+	return nil
+}
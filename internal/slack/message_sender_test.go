@@ -0,0 +1,68 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+func TestMessageSender_SendMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		message    string
+		status     int
+		wantBlocks []block
+		wantErr    bool
+	}{
+		{
+			name:    "sends_mrkdwn_block",
+			message: "price < 100 & stock > 0",
+			status:  http.StatusOK,
+			wantBlocks: []block{
+				{Type: "section", Text: textObject{Type: "mrkdwn", Text: "price &lt; 100 &amp; stock &gt; 0"}},
+			},
+		},
+		{
+			name:    "error_response",
+			message: "hello",
+			status:  http.StatusBadRequest,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got webhookRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.NoError(t, json.Unmarshal(body, &got))
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte("ok"))
+			}))
+			defer server.Close()
+
+			sender := NewMessageSender(MessageSenderConfig{WebhookURL: server.URL})
+			err := sender.SendMessage(context.Background(), domain.Message{CrawlName: "test", Text: tt.message})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBlocks, got.Blocks)
+			assert.Equal(t, "price &lt; 100 &amp; stock &gt; 0", got.Text)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package slack
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_escapeForMrkdwn(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{
+			name: "sample_message",
+			s:    "Hello, *world*!",
+			want: "Hello, *world*!",
+		},
+		{
+			name: "control_characters",
+			s:    "a < b && c > d",
+			want: "a &lt; b &amp;&amp; c &gt; d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := escapeForMrkdwn(tt.s)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_splitMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want []string
+	}{
+		{
+			name: "short_message",
+			msg:  "hello world",
+			want: []string{"hello world"},
+		},
+		{
+			name: "exceeds_max_splits_at_newline",
+			msg:  strings.Repeat("a", maxSectionTextLength-10) + "\n" + strings.Repeat("b", 20),
+			want: []string{
+				strings.Repeat("a", maxSectionTextLength-10) + "\n",
+				strings.Repeat("b", 20),
+			},
+		},
+		{
+			name: "does_not_split_escaped_entity",
+			msg:  strings.Repeat("a", maxSectionTextLength-2) + "&amp;" + "b",
+			want: []string{
+				strings.Repeat("a", maxSectionTextLength-2),
+				"&amp;b",
+			},
+		},
+		{
+			name: "does_not_split_multibyte_character",
+			msg:  strings.Repeat("a", maxSectionTextLength-1) + "가나",
+			want: []string{
+				strings.Repeat("a", maxSectionTextLength-1),
+				"가나",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitMessage(tt.msg)
+			assert.Equal(t, tt.want, got)
+
+			for i, chunk := range got {
+				assert.LessOrEqual(t, len(chunk), maxSectionTextLength, "chunk %d exceeds max length", i)
+			}
+			assert.Equal(t, tt.msg, strings.Join(got, ""), "joined chunks should equal original message")
+		})
+	}
+}
+
+func Test_newWebhookRequests(t *testing.T) {
+	line := strings.Repeat("a", maxSectionTextLength-1) + "\n"
+	msg := strings.Repeat(line, maxBlocks+1)
+
+	reqs := newWebhookRequests(msg)
+	if assert.Len(t, reqs, 2) {
+		assert.Len(t, reqs[0].Blocks, maxBlocks)
+		assert.Len(t, reqs[1].Blocks, 1)
+		assert.LessOrEqual(t, len(reqs[0].Text), maxFallbackTextLength)
+	}
+}
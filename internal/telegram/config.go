@@ -1,6 +1,9 @@
 package telegram
 
+import "github.com/isutare412/crawlert/internal/retry"
+
 type MessageSenderConfig struct {
 	BotToken string
 	ChatID   string
+	Retry    retry.Policy
 }
@@ -12,6 +12,12 @@ type sendMessageRequest struct {
 	ParseMode string `json:"parse_mode,omitempty"`
 }
 
+type errorResponse struct {
+	Parameters struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
 func newSendMessageRequest(chatID, text string) sendMessageRequest {
 	text = truncateLargeMessage(text)
 	text = escapeForMarkdown(text)
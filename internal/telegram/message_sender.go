@@ -7,17 +7,24 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/retry"
 )
 
 const (
+	apiBaseURL     = "https://api.telegram.org"
 	apiSendMessage = "/sendMessage"
 )
 
 type MessageSender struct {
 	httpClient *http.Client
 
-	botToken string
-	chatID   string
+	apiBaseURL  string
+	botToken    string
+	chatID      string
+	retryPolicy retry.Policy
 }
 
 func NewMessageSender(cfg MessageSenderConfig) *MessageSender {
@@ -25,24 +32,37 @@ func NewMessageSender(cfg MessageSenderConfig) *MessageSender {
 	transport.MaxIdleConnsPerHost = 100
 
 	return &MessageSender{
-		httpClient: &http.Client{Transport: transport},
-		botToken:   cfg.BotToken,
-		chatID:     cfg.ChatID,
+		httpClient:  &http.Client{Transport: transport},
+		apiBaseURL:  apiBaseURL,
+		botToken:    cfg.BotToken,
+		chatID:      cfg.ChatID,
+		retryPolicy: cfg.Retry,
 	}
 }
 
-func (s *MessageSender) SendMessage(ctx context.Context, message string) error {
-	if len(message) == 0 {
+// Destination returns the chat ID to which messages are sent.
+func (s *MessageSender) Destination() string {
+	return s.chatID
+}
+
+func (s *MessageSender) SendMessage(ctx context.Context, message domain.Message) error {
+	if len(message.Text) == 0 {
 		return nil
 	}
 
-	req := newSendMessageRequest(s.chatID, message)
+	req := newSendMessageRequest(s.chatID, message.Text)
 	reqBytes, err := json.Marshal(&req)
 	if err != nil {
 		return fmt.Errorf("marshaling message body: %w", err)
 	}
 
-	url := telegramBotAPIBase(s.botToken) + apiSendMessage
+	return retry.Do(ctx, s.retryPolicy, func(ctx context.Context) error {
+		return s.sendRequest(ctx, reqBytes)
+	})
+}
+
+func (s *MessageSender) sendRequest(ctx context.Context, reqBytes []byte) error {
+	url := s.botAPIBase() + apiSendMessage
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBytes))
 	if err != nil {
 		return fmt.Errorf("building http request: %w", err)
@@ -52,7 +72,7 @@ func (s *MessageSender) SendMessage(ctx context.Context, message string) error {
 
 	httpResp, err := s.httpClient.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("doing http request: %w", err)
+		return retry.HTTPError(fmt.Errorf("doing http request: %w", err), nil)
 	}
 	defer httpResp.Body.Close()
 
@@ -62,12 +82,30 @@ func (s *MessageSender) SendMessage(ctx context.Context, message string) error {
 	}
 
 	if httpResp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected response status code %s; body (%s)", httpResp.Status, string(bodyBytes))
+		err := fmt.Errorf("unexpected response status code %s; body (%s)", httpResp.Status, string(bodyBytes))
+		if after, ok := parseRetryAfter(bodyBytes); ok {
+			return retry.Retryable(err, after)
+		}
+		return retry.HTTPError(err, httpResp)
 	}
 
 	return nil
 }
 
-func telegramBotAPIBase(botToken string) string {
-	return fmt.Sprintf("https://api.telegram.org/bot%s", botToken)
+func (s *MessageSender) botAPIBase() string {
+	return fmt.Sprintf("%s/bot%s", s.apiBaseURL, s.botToken)
+}
+
+// parseRetryAfter parses the delay requested by Telegram when requests are
+// rate limited.
+// https://core.telegram.org/bots/api#responseparameters
+func parseRetryAfter(body []byte) (time.Duration, bool) {
+	var resp errorResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return 0, false
+	}
+	if resp.Parameters.RetryAfter <= 0 {
+		return 0, false
+	}
+	return time.Duration(resp.Parameters.RetryAfter) * time.Second, true
 }
@@ -0,0 +1,113 @@
+package telegram
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/retry"
+)
+
+func TestMessageSender_SendMessage(t *testing.T) {
+	policy := retry.Policy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Second, // not below delays requested in tests
+	}
+
+	type response struct {
+		status int
+		body   string
+	}
+	tests := []struct {
+		name         string
+		responses    []response
+		wantAttempts int
+		wantErr      bool
+	}{
+		{
+			name: "success",
+			responses: []response{
+				{status: http.StatusOK, body: `{"ok":true}`},
+			},
+			wantAttempts: 1,
+		},
+		{
+			name: "retry_after_rate_limited",
+			responses: []response{
+				{status: http.StatusTooManyRequests, body: `{"ok":false,"error_code":429,"parameters":{"retry_after":1}}`},
+				{status: http.StatusOK, body: `{"ok":true}`},
+			},
+			wantAttempts: 2,
+		},
+		{
+			name: "retry_on_server_error",
+			responses: []response{
+				{status: http.StatusBadGateway},
+				{status: http.StatusInternalServerError},
+				{status: http.StatusOK, body: `{"ok":true}`},
+			},
+			wantAttempts: 3,
+		},
+		{
+			name: "give_up_after_attempts",
+			responses: []response{
+				{status: http.StatusInternalServerError},
+				{status: http.StatusInternalServerError},
+				{status: http.StatusInternalServerError},
+			},
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name: "no_retry_on_bad_request",
+			responses: []response{
+				{status: http.StatusBadRequest, body: `{"ok":false,"error_code":400}`},
+			},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			var attemptTimes []time.Time
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/bottest-token/sendMessage", r.URL.Path)
+
+				resp := tt.responses[attempts]
+				attempts++
+				attemptTimes = append(attemptTimes, time.Now())
+
+				w.WriteHeader(resp.status)
+				w.Write([]byte(resp.body))
+			}))
+			defer server.Close()
+
+			sender := NewMessageSender(MessageSenderConfig{
+				BotToken: "test-token",
+				ChatID:   "test-chat",
+				Retry:    policy,
+			})
+			sender.apiBaseURL = server.URL
+
+			err := sender.SendMessage(context.Background(), domain.Message{Text: "hello"})
+			assert.Equal(t, tt.wantAttempts, attempts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			if tt.responses[0].status == http.StatusTooManyRequests {
+				assert.GreaterOrEqual(t, attemptTimes[1].Sub(attemptTimes[0]), time.Second)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package template
+
+import "regexp"
+
+var regexPatternVariable = regexp.MustCompile(`\$(?:\{(\w+)\}|(\w+))`)
+
+// Render substitutes $FOO, ${FOO} patterns in template to the value of FOO in
+// variables. Unknown variables are left as they are.
+func Render(template string, variables map[string]string) string {
+	return RenderFunc(template, func(key string) (string, bool) {
+		value, ok := variables[key]
+		return value, ok
+	})
+}
+
+// RenderFunc is like [Render], but values of variables are looked up by
+// lookup.
+func RenderFunc(template string, lookup func(key string) (string, bool)) string {
+	return regexPatternVariable.ReplaceAllStringFunc(template, func(match string) string {
+		groups := regexPatternVariable.FindStringSubmatch(match)
+		key := groups[1] + groups[2]
+
+		if value, ok := lookup(key); ok {
+			return value
+		}
+		return match
+	})
+}
@@ -0,0 +1,58 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	type args struct {
+		template  string
+		variables map[string]string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "multiple_variables",
+			args: args{
+				template: `hello $ONE, bye ${TWO}`,
+				variables: map[string]string{
+					"ONE": "tester1",
+					"TWO": "tester2",
+				},
+			},
+			want: `hello tester1, bye tester2`,
+		},
+		{
+			name: "unknown_variable",
+			args: args{
+				template: `hello $UNKNOWN, bye ${SUSPECT}`,
+				variables: map[string]string{
+					"ONE": "tester1",
+				},
+			},
+			want: `hello $UNKNOWN, bye ${SUSPECT}`,
+		},
+		{
+			name: "variable_followed_by_brace",
+			args: args{
+				template: `{"text":$ONE}`,
+				variables: map[string]string{
+					"ONE": `"tester1"`,
+				},
+			},
+			want: `{"text":"tester1"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.args.template, tt.args.variables)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
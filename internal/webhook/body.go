@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/template"
+)
+
+const (
+	variableMessage   = "MESSAGE"
+	variableCrawlName = "CRAWL_NAME"
+)
+
+// buildBody renders the body template with variables of msg. The message text
+// and the crawl name are available as $MESSAGE and $CRAWL_NAME.
+func buildBody(format Format, bodyTemplate string, msg domain.Message) ([]byte, error) {
+	lookup := func(key string) (string, bool) {
+		switch key {
+		case variableMessage:
+			return encodeString(format, msg.Text), true
+		case variableCrawlName:
+			return encodeString(format, msg.CrawlName), true
+		}
+
+		value, ok := msg.Variables[key]
+		if !ok {
+			return "", false
+		}
+		return encodeValue(format, value), true
+	}
+
+	body := template.RenderFunc(bodyTemplate, lookup)
+	if format == FormatJSON && !json.Valid([]byte(body)) {
+		return nil, fmt.Errorf("rendered body is not a valid json: %s", body)
+	}
+
+	return []byte(body), nil
+}
+
+func encodeString(format Format, s string) string {
+	switch format {
+	case FormatJSON:
+		encoded, _ := json.Marshal(s)
+		return string(encoded)
+	case FormatForm:
+		return url.QueryEscape(s)
+	default:
+		return s
+	}
+}
+
+func encodeValue(format Format, s string) string {
+	if format == FormatJSON && json.Valid([]byte(s)) {
+		return s
+	}
+	return encodeString(format, s)
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"fmt"
+
+	"github.com/isutare412/crawlert/internal/retry"
+)
+
+type MessageSenderConfig struct {
+	URL    string
+	Method string
+	Header map[string]string
+	Format Format
+	Retry  retry.Policy
+
+	// Body is a template of the request body. Default body of the format is
+	// used if empty.
+	Body string
+}
+
+// Format is the format of request body. It decides how variables are
+// substituted in the body template.
+type Format string
+
+const (
+	// FormatJSON substitutes variables as JSON values. MESSAGE and CRAWL_NAME
+	// are encoded as JSON strings. Other variables are inserted as they are
+	// if they are valid JSON, and encoded as JSON strings otherwise.
+	FormatJSON Format = "json"
+	// FormatForm substitutes variables as URL encoded values.
+	FormatForm Format = "form"
+	// FormatText substitutes variables as they are.
+	FormatText Format = "text"
+)
+
+func (f Format) Validate() error {
+	switch f {
+	case FormatJSON, FormatForm, FormatText:
+		return nil
+	default:
+		return fmt.Errorf("unknown webhook format '%s'", f)
+	}
+}
+
+func (f Format) contentType() string {
+	switch f {
+	case FormatJSON:
+		return "application/json"
+	case FormatForm:
+		return "application/x-www-form-urlencoded"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+func (f Format) defaultBody() string {
+	switch f {
+	case FormatJSON:
+		return `{"crawl":$CRAWL_NAME,"message":$MESSAGE}`
+	case FormatForm:
+		return `crawl=$CRAWL_NAME&message=$MESSAGE`
+	default:
+		return `$MESSAGE`
+	}
+}
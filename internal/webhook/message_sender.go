@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+	"github.com/isutare412/crawlert/internal/retry"
+)
+
+type MessageSender struct {
+	httpClient *http.Client
+
+	url         string
+	method      string
+	header      http.Header
+	format      Format
+	body        string
+	retryPolicy retry.Policy
+}
+
+func NewMessageSender(cfg MessageSenderConfig) *MessageSender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 100
+
+	method := cfg.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	body := cfg.Body
+	if body == "" {
+		body = cfg.Format.defaultBody()
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", cfg.Format.contentType())
+	for k, v := range cfg.Header {
+		header.Set(k, v)
+	}
+
+	return &MessageSender{
+		httpClient:  &http.Client{Transport: transport},
+		url:         cfg.URL,
+		method:      method,
+		header:      header,
+		format:      cfg.Format,
+		body:        body,
+		retryPolicy: cfg.Retry,
+	}
+}
+
+// Destination returns the URL to which messages are sent.
+func (s *MessageSender) Destination() string {
+	return s.url
+}
+
+func (s *MessageSender) SendMessage(ctx context.Context, message domain.Message) error {
+	if len(message.Text) == 0 {
+		return nil
+	}
+
+	reqBody, err := buildBody(s.format, s.body, message)
+	if err != nil {
+		return fmt.Errorf("building request body: %w", err)
+	}
+
+	return retry.Do(ctx, s.retryPolicy, func(ctx context.Context) error {
+		return s.sendRequest(ctx, reqBody)
+	})
+}
+
+func (s *MessageSender) sendRequest(ctx context.Context, reqBody []byte) error {
+	httpReq, err := http.NewRequestWithContext(ctx, s.method, s.url, bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("building http request: %w", err)
+	}
+	httpReq.Header = s.header.Clone()
+
+	httpResp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return retry.HTTPError(fmt.Errorf("doing http request: %w", err), nil)
+	}
+	defer httpResp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		err := fmt.Errorf("unexpected response status code %s; body (%s)", httpResp.Status, string(bodyBytes))
+		return retry.HTTPError(err, httpResp)
+	}
+
+	return nil
+}
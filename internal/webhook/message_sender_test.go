@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/isutare412/crawlert/internal/core/domain"
+)
+
+func TestMessageSender_SendMessage(t *testing.T) {
+	message := domain.Message{
+		CrawlName: "JSONPlaceHolder",
+		Text:      "Found \"titles\"\nof user 1",
+		Variables: map[string]string{
+			"TITLES": `["foo","bar"]`,
+			"CODE":   `HTTP 500`,
+		},
+	}
+
+	tests := []struct {
+		name            string
+		cfg             MessageSenderConfig
+		status          int
+		wantMethod      string
+		wantContentType string
+		wantHeader      map[string]string
+		wantBody        string
+		wantErr         bool
+	}{
+		{
+			name: "json_body",
+			cfg: MessageSenderConfig{
+				Format: FormatJSON,
+				Header: map[string]string{"Authorization": "Bearer token"},
+				Body:   `{"summary": $MESSAGE, "titles": $TITLES, "code": $CODE, "source": ${CRAWL_NAME}}`,
+			},
+			status:          http.StatusOK,
+			wantMethod:      http.MethodPost,
+			wantContentType: "application/json",
+			wantHeader:      map[string]string{"Authorization": "Bearer token"},
+			wantBody:        `{"summary": "Found \"titles\"\nof user 1", "titles": ["foo","bar"], "code": "HTTP 500", "source": "JSONPlaceHolder"}`,
+		},
+		{
+			name: "invalid_json_body",
+			cfg: MessageSenderConfig{
+				Format: FormatJSON,
+				Body:   `{"source": "crawlert/$CRAWL_NAME"}`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "default_json_body",
+			cfg: MessageSenderConfig{
+				Format: FormatJSON,
+			},
+			status:          http.StatusAccepted,
+			wantMethod:      http.MethodPost,
+			wantContentType: "application/json",
+			wantBody:        `{"crawl":"JSONPlaceHolder","message":"Found \"titles\"\nof user 1"}`,
+		},
+		{
+			name: "form_body",
+			cfg: MessageSenderConfig{
+				Method: http.MethodPut,
+				Format: FormatForm,
+				Body:   `text=$MESSAGE&titles=$TITLES`,
+			},
+			status:          http.StatusOK,
+			wantMethod:      http.MethodPut,
+			wantContentType: "application/x-www-form-urlencoded",
+			wantBody:        `text=Found+%22titles%22%0Aof+user+1&titles=%5B%22foo%22%2C%22bar%22%5D`,
+		},
+		{
+			name: "text_body_with_custom_content_type",
+			cfg: MessageSenderConfig{
+				Format: FormatText,
+				Header: map[string]string{"Content-Type": "text/markdown"},
+				Body:   `[$CRAWL_NAME] $MESSAGE`,
+			},
+			status:          http.StatusOK,
+			wantMethod:      http.MethodPost,
+			wantContentType: "text/markdown",
+			wantBody:        "[JSONPlaceHolder] Found \"titles\"\nof user 1",
+		},
+		{
+			name: "error_response",
+			cfg: MessageSenderConfig{
+				Format: FormatText,
+			},
+			status:          http.StatusInternalServerError,
+			wantMethod:      http.MethodPost,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "Found \"titles\"\nof user 1",
+			wantErr:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				assert.Equal(t, tt.wantMethod, r.Method)
+				assert.Equal(t, tt.wantContentType, r.Header.Get("Content-Type"))
+				for k, v := range tt.wantHeader {
+					assert.Equal(t, v, r.Header.Get(k))
+				}
+
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantBody, string(body))
+
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			cfg := tt.cfg
+			cfg.URL = server.URL
+			sender := NewMessageSender(cfg)
+
+			err := sender.SendMessage(context.Background(), message)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, called)
+		})
+	}
+}